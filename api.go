@@ -1,6 +1,9 @@
 package art
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // Node types.
 const (
@@ -24,14 +27,39 @@ const (
 
 	// Iterate in reverse order.
 	TraverseReverse = 4
+
+	// Iterate level by level, starting from the root (level 0).
+	// The children of a node are visited in ascending order, or in descending order with TraverseReverse.
+	// The visited nodes implement the LevelNode interface.
+	TraverseBreadthFirst = 8
+
+	// Iterate over leaf nodes with an Iterator that survives modifications of the tree.
+	// The iterator remembers the last returned key. After Insert or Delete it continues
+	// with the first key after the last returned one (before it with TraverseReverse),
+	// so the keys inserted behind the iterator are not visited, the keys inserted ahead are,
+	// and the deleted keys are not. The TraverseNode and TraverseBreadthFirst options are ignored.
+	// With ForEach, the callback can modify the tree. The frozen tree ignores this option.
+	TraverseResumable = 16
 )
 
 // These errors can be returned when iteration over the tree.
 var (
 	ErrConcurrentModification = errors.New("concurrent modification has been detected")
 	ErrNoMoreNodes            = errors.New("there are no more nodes in the tree")
+	ErrNoLeafToRemove         = errors.New("there is no leaf to remove")
+	ErrReadOnlyTree           = errors.New("the tree is read-only")
 )
 
+// ErrInvalidSnapshot is returned when decoding a malformed tree snapshot.
+var ErrInvalidSnapshot = errors.New("invalid tree snapshot")
+
+// ErrUnsupportedTree is returned by the functions that need a tree created by New or NewWithOptions,
+// e.g. UnmarshalTree or Stats, when they get a custom implementation of the Tree interface.
+var ErrUnsupportedTree = errors.New("unsupported tree implementation")
+
+// ErrTreeCorrupted is returned by Verify when a tree invariant is violated, see VerifyError.
+var ErrTreeCorrupted = errors.New("tree is corrupted")
+
 // Kind is a node type.
 type Kind int
 
@@ -42,6 +70,8 @@ func (k Kind) String() string {
 
 // Key represents the type used for keys in the Adaptive Radix Tree.
 // It can consist of any byte sequence, including Unicode characters and null bytes.
+// The empty key is a valid key that sorts before all other keys, nil and Key{} are the same key.
+// The keys can be up to 4GB long, including the prefixes shared with other keys.
 type Key []byte
 
 // Value is an interface representing the value type stored in the tree.
@@ -53,6 +83,10 @@ type Value interface{}
 // If the callback function returns false, the iteration is terminated early.
 type Callback func(node Node) (cont bool)
 
+// ErrorCallback defines the function type used during context-aware tree traversal, see ForEachCtx.
+// If the callback function returns an error, the iteration is terminated and the error is returned.
+type ErrorCallback func(node Node) error
+
 // Node represents a node within the Adaptive Radix Tree.
 type Node interface {
 	// Kind returns the type of the node, distinguishing between leaf and internal nodes.
@@ -69,6 +103,67 @@ type Node interface {
 	Value() Value
 }
 
+// InnerNode provides read-only introspection of the tree nodes.
+// Nodes passed to the callbacks and returned by the iterators of the mutable tree implement it,
+// so inner nodes visited with TraverseNode or TraverseAll can be inspected with a type assertion:
+//
+//	tree.ForEach(func(node art.Node) bool {
+//		if in, ok := node.(art.InnerNode); ok && node.Kind() != art.Leaf {
+//			fmt.Printf("%q: %d children\n", in.Path(), in.ChildCount())
+//		}
+//		return true
+//	}, art.TraverseNode)
+//
+// The returned keys share memory with the tree and must not be modified.
+type InnerNode interface {
+	Node
+
+	// Prefix returns the compressed path of the node, i.e. the key bytes
+	// shared by all keys under the node after the parent's child byte.
+	// The full prefix is returned even if the node stores only its first bytes.
+	// It returns nil for leaf nodes.
+	Prefix() Key
+
+	// PrefixLen returns the length of the node's prefix, 0 for leaf nodes.
+	PrefixLen() int
+
+	// Depth returns the offset of the node's prefix in the keys,
+	// i.e. the number of key bytes consumed on the path from the root to the node.
+	// For leaf nodes it returns the key length.
+	Depth() int
+
+	// ChildCount returns the number of children including the terminator child.
+	ChildCount() int
+
+	// Children returns the children in ascending key order.
+	// The terminator child, whose key ends at the node, comes first.
+	Children() []Child
+
+	// Path returns the key bytes from the root up to and including the node's prefix.
+	// For leaf nodes it returns the key.
+	Path() Key
+}
+
+// LevelNode is a node visited by the breadth-first traversal, see TraverseBreadthFirst.
+type LevelNode interface {
+	Node
+
+	// Level returns the number of edges between the root and the node.
+	Level() int
+}
+
+// Child is a child of an inner node, see InnerNode.Children.
+type Child struct {
+	// Key is the key byte that leads to the child, 0 for the terminator child.
+	Key byte
+
+	// Terminator is true for the child whose key ends at the parent node.
+	Terminator bool
+
+	// Node is the child node.
+	Node Node
+}
+
 // Iterator provides a mechanism to traverse nodes in key order within the tree.
 type Iterator interface {
 	// HasNext returns true if there are more nodes to visit during the iteration.
@@ -81,9 +176,111 @@ type Iterator interface {
 	// If the tree has been structurally modified since the iterator was created,
 	// it returns an ErrConcurrentModification error.
 	Next() (Node, error)
+
+	// Remove deletes the leaf returned by the last call to Next from the tree
+	// and keeps the iterator valid, so the iteration continues with the next node.
+	// It returns ErrNoLeafToRemove if Next has not been called, the last returned node
+	// is not a leaf or it has already been removed, and ErrReadOnlyTree for the frozen tree.
+	// If the tree has been modified by other means, it returns ErrConcurrentModification.
+	Remove() error
+}
+
+// Cursor is a bidirectional position in the ordered leaves of the tree.
+// A new cursor is not valid until it is positioned by First, Last or Seek.
+// The direction can be changed at any point by calling Next or Prev.
+// Moving past either end makes the cursor invalid, reposition it with First, Last or Seek.
+// If the tree is modified, Next and Prev continue from the current key,
+// or from its neighbor in the direction of the move if the current key has been deleted.
+type Cursor interface {
+	// First moves the cursor to the smallest key and returns true if the tree is not empty.
+	First() bool
+
+	// Last moves the cursor to the largest key and returns true if the tree is not empty.
+	Last() bool
+
+	// Seek moves the cursor to the smallest key greater than or equal to the key
+	// and returns true if such a key exists.
+	Seek(key Key) bool
+
+	// Next moves the cursor to the next key in ascending order and returns true if it exists.
+	Next() bool
+
+	// Prev moves the cursor to the previous key in ascending order and returns true if it exists.
+	Prev() bool
+
+	// Valid returns true if the cursor is positioned at a key.
+	Valid() bool
+
+	// Key returns the key at the cursor position or nil if the cursor is not valid.
+	Key() Key
+
+	// Value returns the value at the cursor position or nil if the cursor is not valid.
+	Value() Value
+}
+
+// ReadOnlyTree is the read-only interface of the tree, implemented by the frozen tree
+// and the trees created by New and NewWithOptions.
+type ReadOnlyTree interface {
+	Sequences
+
+	// Search retrieves the value associated with the specified key in the tree.
+	// If the key exists, it returns the value and true.
+	// If the key does not exist, it returns nil and false.
+	Search(key Key) (value Value, found bool)
+
+	// ForEach iterates over all the nodes in the tree, invoking a provided callback function for each node.
+	// By default, it processes leaf nodes in ascending order.
+	// The iteration can be customized using options:
+	// - Pass TraverseReverse to iterate over nodes in descending order.
+	// - Pass TraverseBreadthFirst to iterate level by level, the nodes implement LevelNode.
+	//   The iteration stops if the callback modifies the tree.
+	// The iteration stops if the callback function returns false, allowing for early termination.
+	ForEach(callback Callback, options ...int)
+
+	// ForEachPrefix iterates over all leaf nodes whose keys start with the specified keyPrefix,
+	// invoking a provided callback function for each matching node.
+	// The nil and empty prefixes match all keys, including the empty key.
+	// By default, the iteration processes nodes in ascending order.
+	// Use the TraverseReverse option to iterate over nodes in descending order.
+	// Iteration stops if the callback function returns false, allowing for early termination.
+	ForEachPrefix(keyPrefix Key, callback Callback, options ...int)
+
+	// ForEachCtx iterates over the nodes in the tree like ForEach, invoking the callback for each node.
+	// The iteration stops on the first error returned by the callback or when the context is done,
+	// and that error or the context error is returned. It returns nil if all nodes have been visited.
+	ForEachCtx(ctx context.Context, callback ErrorCallback, options ...int) error
+
+	// ForEachPrefixCtx iterates over the leaf nodes whose keys start with the specified keyPrefix
+	// like ForEachPrefix, and stops on the first error or when the context is done like ForEachCtx.
+	ForEachPrefixCtx(ctx context.Context, keyPrefix Key, callback ErrorCallback, options ...int) error
+
+	// Iterator returns an iterator for traversing leaf nodes in the tree.
+	// By default, the iteration occurs in ascending order.
+	// To traverse nodes in reverse (descending) order, pass the TraverseReverse option.
+	// To traverse nodes level by level, pass the TraverseBreadthFirst option.
+	Iterator(options ...int) Iterator
+
+	// Minimum retrieves the leaf node with the smallest key in the tree.
+	// If such a leaf is found, it returns its value and true.
+	// If the tree is empty, it returns nil and false.
+	Minimum() (Value, bool)
+
+	// Maximum retrieves the leaf node with the largest key in the tree.
+	// If such a leaf is found, it returns its value and true.
+	// If the tree is empty, it returns nil and false.
+	Maximum() (Value, bool)
+
+	// Size returns the number of key-value pairs stored in the tree.
+	Size() int
 }
 
 // Tree is an Adaptive Radix Tree interface.
+// The trees created by New and NewWithOptions also implement ReadOnlyTree,
+// e.g. for the context-aware traversal and the sequences:
+//
+//	if rt, ok := tree.(art.ReadOnlyTree); ok {
+//		err = rt.ForEachCtx(ctx, callback)
+//	}
 type Tree interface {
 	// Insert adds a new key-value pair into the tree.
 	// If the key already exists in the tree, it updates its value and returns the old value along with true.
@@ -104,11 +301,14 @@ type Tree interface {
 	// By default, it processes leaf nodes in ascending order.
 	// The iteration can be customized using options:
 	// - Pass TraverseReverse to iterate over nodes in descending order.
+	// - Pass TraverseBreadthFirst to iterate level by level, the nodes implement LevelNode.
+	//   The iteration stops if the callback modifies the tree.
 	// The iteration stops if the callback function returns false, allowing for early termination.
 	ForEach(callback Callback, options ...int)
 
 	// ForEachPrefix iterates over all leaf nodes whose keys start with the specified keyPrefix,
 	// invoking a provided callback function for each matching node.
+	// The nil and empty prefixes match all keys, including the empty key.
 	// By default, the iteration processes nodes in ascending order.
 	// Use the TraverseReverse option to iterate over nodes in descending order.
 	// Iteration stops if the callback function returns false, allowing for early termination.
@@ -117,6 +317,7 @@ type Tree interface {
 	// Iterator returns an iterator for traversing leaf nodes in the tree.
 	// By default, the iteration occurs in ascending order.
 	// To traverse nodes in reverse (descending) order, pass the TraverseReverse option.
+	// To traverse nodes level by level, pass the TraverseBreadthFirst option.
 	Iterator(options ...int) Iterator
 
 	// Minimum retrieves the leaf node with the smallest key in the tree.
@@ -133,7 +334,28 @@ type Tree interface {
 	Size() int
 }
 
+// FrozenTree is a compact read-only representation of the tree, see Freeze.
+type FrozenTree interface {
+	ReadOnlyTree
+
+	// Thaw converts the frozen tree back into a mutable tree.
+	Thaw() Tree
+}
+
 // New creates a new adaptive radix tree.
 func New() Tree {
 	return newTree()
 }
+
+// NewWithOptions creates a new adaptive radix tree with the given options.
+func NewWithOptions(opts ...TreeOption) Tree {
+	var options treeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	tr := newTree()
+	tr.hooks = options.hooks
+
+	return tr
+}
@@ -0,0 +1,432 @@
+// Package bitcask implements a Bitcask-style log-structured key/value store
+// that uses an Adaptive Radix Tree as the in-memory key directory.
+//
+// Values are appended to segment data files and the tree keeps only
+// the location of every value (segment, offset and length), so the memory
+// footprint depends on the number and size of keys, not on the size of values.
+//
+// A segment is sealed when it grows beyond the configured size. Sealed segments
+// are immutable and get a hint file with the keys and value locations,
+// which is used to rebuild the key directory quickly on Open.
+// Compaction rewrites live values of sealed segments into a new segment
+// and removes the old ones.
+//
+// Usage:
+//
+//	db, err := bitcask.Open("/var/lib/blobs")
+//	if err != nil {
+//	    return err
+//	}
+//	defer db.Close()
+//
+//	if err := db.Put([]byte("user:1"), blob); err != nil {
+//	    return err
+//	}
+//
+//	value, err := db.Get([]byte("user:1"))
+package bitcask
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+)
+
+// These errors can be returned by the DB methods.
+var (
+	ErrKeyNotFound = errors.New("bitcask: key not found")
+	ErrEmptyKey    = errors.New("bitcask: key is empty")
+	ErrClosed      = errors.New("bitcask: database is closed")
+	ErrCorrupted   = errors.New("bitcask: corrupted data")
+	ErrTooLarge    = errors.New("bitcask: key or value is too large")
+)
+
+const (
+	defaultMaxSegmentSize  = 64 << 20 // 64MiB
+	defaultCompactionRatio = 0.5
+)
+
+// options contains the DB options.
+type options struct {
+	maxSegmentSize     int64
+	syncWrites         bool
+	compactionInterval time.Duration
+	compactionRatio    float64
+}
+
+// Option is a function that sets an option for Open.
+type Option func(opts *options)
+
+// WithMaxSegmentSize sets the size after which the active segment is sealed
+// and a new one is started.
+func WithMaxSegmentSize(size int64) Option {
+	return func(opts *options) {
+		opts.maxSegmentSize = size
+	}
+}
+
+// WithSyncWrites makes every Put and Delete call fsync the active segment.
+func WithSyncWrites(sync bool) Option {
+	return func(opts *options) {
+		opts.syncWrites = sync
+	}
+}
+
+// WithBackgroundCompaction starts a goroutine that checks the database every interval
+// and compacts it when the share of dead bytes reaches the given ratio (0..1].
+func WithBackgroundCompaction(interval time.Duration, ratio float64) Option {
+	return func(opts *options) {
+		opts.compactionInterval = interval
+		opts.compactionRatio = ratio
+	}
+}
+
+// DB is a Bitcask-style key/value store.
+// It is safe for concurrent use.
+type DB struct {
+	mu        sync.RWMutex
+	compactMu sync.Mutex // serializes compactions
+
+	dir      string
+	opts     options
+	keydir   art.Tree            // key -> valuePointer
+	segments map[uint32]*segment // all open segments including the active one
+	active   *segment            // segment the writes go to
+	hints    []hint              // hints of the active segment
+	nextID   uint32              // id of the next segment to create
+	closed   bool                // true after Close
+	total    int64               // total size of all segments
+	live     int64               // size of records referenced by the key directory
+	stop     chan struct{}       // closed to stop background compaction
+	done     chan struct{}       // closed when background compaction exits
+	stopOnce sync.Once           // closes stop once, Close may be called concurrently
+}
+
+// Open opens the database stored in dir, creating the directory if needed.
+// The key directory is rebuilt from hint files, or from data files
+// for segments without hints.
+func Open(dir string, opts ...Option) (*DB, error) {
+	options := options{
+		maxSegmentSize:  defaultMaxSegmentSize,
+		compactionRatio: defaultCompactionRatio,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	db := &DB{
+		dir:      dir,
+		opts:     options,
+		keydir:   art.New(),
+		segments: make(map[uint32]*segment),
+	}
+
+	if err := db.load(); err != nil {
+		db.closeSegments()
+
+		return nil, err
+	}
+
+	if options.compactionInterval > 0 {
+		db.stop = make(chan struct{})
+		db.done = make(chan struct{})
+
+		go db.compactInBackground()
+	}
+
+	return db, nil
+}
+
+// load rebuilds the key directory from the segments on disk
+// and starts a new active segment.
+func (db *DB) load() error {
+	ids, err := listSegments(db.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		seg, err := openSegment(db.dir, id)
+		if err != nil {
+			return err
+		}
+
+		db.segments[id] = seg
+		db.total += seg.size
+		db.nextID = id + 1
+
+		hints, err := readHintFile(db.dir, id)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+
+			if hints, err = seg.scan(); err != nil {
+				return err
+			}
+
+			// the segment is sealed from now on, save its hints for the next Open.
+			if err = writeHintFile(db.dir, id, hints); err != nil {
+				return err
+			}
+		}
+
+		for _, h := range hints {
+			db.apply(h)
+		}
+	}
+
+	return db.rotate()
+}
+
+// apply updates the key directory with a single record.
+func (db *DB) apply(h hint) {
+	if h.tombstone {
+		if old, deleted := db.keydir.Delete(h.key); deleted {
+			db.live -= old.(valuePointer).recordSize(len(h.key))
+		}
+
+		return
+	}
+
+	if old, updated := db.keydir.Insert(h.key, h.ptr); updated {
+		db.live -= old.(valuePointer).recordSize(len(h.key))
+	}
+
+	db.live += h.ptr.recordSize(len(h.key))
+}
+
+// rotate seals the active segment, if any, and starts a new one.
+func (db *DB) rotate() error {
+	if db.active != nil {
+		if err := db.active.file.Sync(); err != nil {
+			return err
+		}
+
+		if err := writeHintFile(db.dir, db.active.id, db.hints); err != nil {
+			return err
+		}
+	}
+
+	seg, err := openSegment(db.dir, db.nextID)
+	if err != nil {
+		return err
+	}
+
+	db.nextID++
+	db.segments[seg.id] = seg
+	db.active = seg
+	db.hints = nil
+
+	return nil
+}
+
+// Get returns the value stored for the key.
+// It returns ErrKeyNotFound if the key does not exist.
+func (db *DB) Get(key []byte) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return nil, ErrClosed
+	}
+
+	val, found := db.keydir.Search(key)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	ptr := val.(valuePointer)
+
+	return db.segments[ptr.segment].read(ptr)
+}
+
+// Has returns true if the key exists.
+func (db *DB) Has(key []byte) (bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return false, ErrClosed
+	}
+
+	_, found := db.keydir.Search(key)
+
+	return found, nil
+}
+
+// Put stores the value for the key, replacing the previous one.
+func (db *DB) Put(key, value []byte) error {
+	if len(key) == 0 {
+		return ErrEmptyKey
+	}
+
+	if uint64(len(key)) >= tombstoneLen || uint64(len(value)) >= tombstoneLen {
+		return ErrTooLarge
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.write(key, value, false)
+}
+
+// Delete removes the key. Deleting a missing key is not an error.
+func (db *DB) Delete(key []byte) error {
+	if len(key) == 0 {
+		return ErrEmptyKey
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	if _, found := db.keydir.Search(key); !found {
+		return nil
+	}
+
+	return db.write(key, nil, true)
+}
+
+// write appends a record to the active segment and updates the key directory.
+// db.mu must be held.
+func (db *DB) write(key, value []byte, tombstone bool) error {
+	if db.closed {
+		return ErrClosed
+	}
+
+	ptr, err := db.active.append(key, value, tombstone)
+	if err != nil {
+		return err
+	}
+
+	if db.opts.syncWrites {
+		if err := db.active.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	h := hint{key: append([]byte(nil), key...), ptr: ptr, tombstone: tombstone}
+	if tombstone {
+		h.ptr.length = 0
+	}
+
+	db.hints = append(db.hints, h)
+	db.total += h.ptr.recordSize(len(key))
+	db.apply(h)
+
+	if db.active.size >= db.opts.maxSegmentSize {
+		return db.rotate()
+	}
+
+	return nil
+}
+
+// Prefix calls fn for every key with the given prefix in ascending key order.
+// Values are read from disk one at a time, so the scan never holds more than
+// one value in memory. The scan stops at the first error returned by fn.
+// fn must not modify the database.
+func (db *DB) Prefix(prefix []byte, fn func(key, value []byte) error) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	var err error
+
+	db.keydir.ForEachPrefix(prefix, func(node art.Node) bool {
+		ptr := node.Value().(valuePointer)
+
+		var value []byte
+		if value, err = db.segments[ptr.segment].read(ptr); err != nil {
+			return false
+		}
+
+		err = fn(node.Key(), value)
+
+		return err == nil
+	})
+
+	return err
+}
+
+// Len returns the number of keys in the database.
+func (db *DB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.keydir.Size()
+}
+
+// Sync flushes the active segment to stable storage.
+func (db *DB) Sync() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	return db.active.file.Sync()
+}
+
+// Close stops background compaction, seals the active segment and closes all files.
+func (db *DB) Close() error {
+	if db.stop != nil {
+		db.stopOnce.Do(func() {
+			close(db.stop)
+			<-db.done
+		})
+	}
+
+	db.compactMu.Lock()
+	defer db.compactMu.Unlock()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	db.closed = true
+
+	err := db.active.file.Sync()
+	if err == nil {
+		err = writeHintFile(db.dir, db.active.id, db.hints)
+	}
+
+	if closeErr := db.closeSegments(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// closeSegments closes all segment files.
+func (db *DB) closeSegments() error {
+	var err error
+
+	for id, seg := range db.segments {
+		if closeErr := seg.close(); err == nil {
+			err = closeErr
+		}
+
+		delete(db.segments, id)
+	}
+
+	return err
+}
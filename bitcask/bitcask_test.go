@@ -0,0 +1,421 @@
+package bitcask
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func blob(i, size int) []byte {
+	return bytes.Repeat([]byte(fmt.Sprintf("%06d", i)), size/6+1)[:size]
+}
+
+func TestPutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	require.NoError(t, db.Put([]byte("alpha"), []byte("1")))
+	require.NoError(t, db.Put([]byte("beta"), []byte("2")))
+	require.NoError(t, db.Put([]byte("alpha"), []byte("3")))
+
+	value, err := db.Get([]byte("alpha"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("3"), value)
+	assert.Equal(t, 2, db.Len())
+
+	require.NoError(t, db.Delete([]byte("alpha")))
+	require.NoError(t, db.Delete([]byte("missing")))
+
+	_, err = db.Get([]byte("alpha"))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	found, err := db.Has([]byte("alpha"))
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = db.Has([]byte("beta"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, db.Len())
+
+	assert.ErrorIs(t, db.Put(nil, []byte("x")), ErrEmptyKey)
+	assert.ErrorIs(t, db.Delete(nil), ErrEmptyKey)
+}
+
+func TestReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	db, err := Open(dir, WithMaxSegmentSize(1024))
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, db.Put([]byte(fmt.Sprintf("key%03d", i)), blob(i, 100)))
+	}
+
+	for i := 0; i < 100; i += 3 {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%03d", i))))
+	}
+
+	require.NoError(t, db.Put([]byte("key001"), []byte("updated")))
+	require.NoError(t, db.Close())
+	assert.ErrorIs(t, db.Close(), ErrClosed)
+
+	_, err = db.Has([]byte("key001"))
+	assert.ErrorIs(t, err, ErrClosed)
+
+	check := func(db *DB) {
+		t.Helper()
+
+		assert.Equal(t, 66, db.Len())
+
+		for i := 0; i < 100; i++ {
+			value, err := db.Get([]byte(fmt.Sprintf("key%03d", i)))
+
+			switch {
+			case i%3 == 0:
+				assert.ErrorIs(t, err, ErrKeyNotFound)
+			case i == 1:
+				assert.Equal(t, []byte("updated"), value)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, blob(i, 100), value)
+			}
+		}
+	}
+
+	// reopen using hint files
+	db, err = Open(dir)
+	require.NoError(t, err)
+	check(db)
+	require.NoError(t, db.Close())
+
+	// reopen by scanning data files
+	hints, err := filepath.Glob(filepath.Join(dir, "*"+hintFileExt))
+	require.NoError(t, err)
+
+	for _, path := range hints {
+		require.NoError(t, os.Remove(path))
+	}
+
+	db, err = Open(dir)
+	require.NoError(t, err)
+	check(db)
+	require.NoError(t, db.Close())
+}
+
+func TestReopenTornTail(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	db, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Put([]byte("a"), []byte("first")))
+	require.NoError(t, db.Put([]byte("b"), []byte("second")))
+
+	// simulate a crash in the middle of the last write
+	path := db.active.file.Name()
+	size := db.active.size
+	require.NoError(t, db.closeSegments())
+	require.NoError(t, os.Truncate(path, size-3))
+
+	db, err = Open(dir)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	value, err := db.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first"), value)
+
+	_, err = db.Get([]byte("b"))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+var errInjected = errors.New("injected failure")
+
+// failingFile is a segment file that fails the writes halfway and the truncations on demand.
+type failingFile struct {
+	*os.File
+	failWrite    bool
+	failTruncate bool
+}
+
+func (f *failingFile) Write(b []byte) (int, error) {
+	if f.failWrite {
+		n, _ := f.File.Write(b[:len(b)/2])
+
+		return n, errInjected
+	}
+
+	return f.File.Write(b)
+}
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.failTruncate {
+		return errInjected
+	}
+
+	return f.File.Truncate(size)
+}
+
+func TestPartialWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	db, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Put([]byte("a"), []byte("first")))
+
+	file := &failingFile{File: db.active.file.(*os.File), failWrite: true}
+	db.active.file = file
+	size := db.active.size
+
+	require.ErrorIs(t, db.Put([]byte("b"), []byte("second")), errInjected)
+
+	info, err := file.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, size, info.Size())
+
+	file.failWrite = false
+	require.NoError(t, db.Put([]byte("c"), []byte("third")))
+
+	// the torn record can not be removed, so the segment rejects further writes.
+	file.failWrite, file.failTruncate = true, true
+	require.ErrorIs(t, db.Put([]byte("d"), []byte("fourth")), errInjected)
+
+	file.failWrite, file.failTruncate = false, false
+	require.ErrorIs(t, db.Put([]byte("e"), []byte("fifth")), errInjected)
+
+	// the torn tail is dropped when the segment is scanned.
+	require.NoError(t, db.closeSegments())
+
+	db, err = Open(dir)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	for key, want := range map[string]string{"a": "first", "c": "third"} {
+		value, err := db.Get([]byte(key))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(want), value)
+	}
+
+	for _, key := range []string{"b", "d", "e"} {
+		_, err := db.Get([]byte(key))
+		assert.ErrorIs(t, err, ErrKeyNotFound, key)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	for _, key := range []string{"user:2", "user:1", "group:1", "user:3"} {
+		require.NoError(t, db.Put([]byte(key), []byte("v-"+key)))
+	}
+
+	var keys []string
+
+	err = db.Prefix([]byte("user:"), func(key, value []byte) error {
+		assert.Equal(t, "v-"+string(key), string(value))
+		keys = append(keys, string(key))
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user:1", "user:2", "user:3"}, keys)
+
+	errStop := fmt.Errorf("stop")
+	calls := 0
+	err = db.Prefix([]byte("user:"), func(_, _ []byte) error {
+		calls++
+
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	db, err := Open(dir, WithMaxSegmentSize(4096))
+	require.NoError(t, err)
+
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 50; i++ {
+			require.NoError(t, db.Put([]byte(fmt.Sprintf("key%03d", i)), blob(round*100+i, 200)))
+		}
+	}
+
+	for i := 0; i < 50; i += 2 {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%03d", i))))
+	}
+
+	assert.Greater(t, db.DeadRatio(), 0.8)
+
+	require.NoError(t, db.Compact())
+	assert.Less(t, db.DeadRatio(), 0.01)
+
+	data, err := filepath.Glob(filepath.Join(dir, "*"+dataFileExt))
+	require.NoError(t, err)
+	assert.Len(t, data, 2) // compacted + active
+
+	check := func(db *DB) {
+		t.Helper()
+
+		assert.Equal(t, 25, db.Len())
+
+		for i := 0; i < 50; i++ {
+			value, err := db.Get([]byte(fmt.Sprintf("key%03d", i)))
+			if i%2 == 0 {
+				assert.ErrorIs(t, err, ErrKeyNotFound)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, blob(400+i, 200), value)
+			}
+		}
+	}
+
+	check(db)
+	require.NoError(t, db.Close())
+
+	db, err = Open(dir)
+	require.NoError(t, err)
+	check(db)
+	require.NoError(t, db.Close())
+}
+
+func TestCompactConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(t.TempDir(), WithMaxSegmentSize(2048))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	for i := 0; i < 200; i++ {
+		require.NoError(t, db.Put([]byte(fmt.Sprintf("key%03d", i)), blob(i, 64)))
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 200; i++ {
+			key := []byte(fmt.Sprintf("key%03d", i))
+			if i%2 == 0 {
+				assert.NoError(t, db.Delete(key))
+			} else {
+				assert.NoError(t, db.Put(key, blob(1000+i, 64)))
+			}
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, db.Compact())
+	}
+
+	wg.Wait()
+	require.NoError(t, db.Compact())
+
+	assert.Equal(t, 100, db.Len())
+
+	for i := 0; i < 200; i++ {
+		value, err := db.Get([]byte(fmt.Sprintf("key%03d", i)))
+		if i%2 == 0 {
+			assert.ErrorIs(t, err, ErrKeyNotFound)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, blob(1000+i, 64), value)
+		}
+	}
+}
+
+func TestBackgroundCompaction(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(t.TempDir(), WithBackgroundCompaction(10*time.Millisecond, 0.5))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.Put([]byte("key"), blob(i, 1000)))
+	}
+
+	assert.Eventually(t, func() bool {
+		return db.DeadRatio() < 0.5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	value, err := db.Get([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, blob(9, 1000), value)
+	require.NoError(t, db.Close())
+}
+
+func TestConcurrentClose(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(t.TempDir(), WithMaxSegmentSize(1024), WithBackgroundCompaction(time.Millisecond, 0))
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, db.Put([]byte(fmt.Sprintf("key%02d", i%10)), blob(i, 100)))
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		closed int
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			err := db.Compact()
+			assert.True(t, err == nil || errors.Is(err, ErrClosed), "%v", err)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if err := db.Close(); err == nil {
+				mu.Lock()
+				closed++
+				mu.Unlock()
+			} else {
+				assert.ErrorIs(t, err, ErrClosed)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, closed)
+}
@@ -0,0 +1,219 @@
+package bitcask
+
+import (
+	"os"
+	"time"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+)
+
+// movedValue tracks a live value copied by compaction.
+type movedValue struct {
+	key    []byte
+	oldPtr valuePointer
+	newPtr valuePointer
+}
+
+// DeadRatio returns the share of segment bytes that are no longer referenced
+// by the key directory, i.e. overwritten values, deleted values and tombstones.
+func (db *DB) DeadRatio() float64 {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.deadRatio()
+}
+
+// deadRatio returns the share of dead bytes. db.mu must be held.
+func (db *DB) deadRatio() float64 {
+	if db.total == 0 {
+		return 0
+	}
+
+	return float64(db.total-db.live) / float64(db.total)
+}
+
+// Compact rewrites live values of all sealed segments into a single new segment
+// and removes the old segments.
+//
+// The active segment is sealed first, so compaction works only with immutable files
+// and does not block readers and writers while values are copied.
+// Once the new segment is durable, the leaf pointers are switched to it in one step
+// under the write lock. Keys updated or deleted during compaction keep their newer values.
+func (db *DB) Compact() error {
+	db.compactMu.Lock()
+	defer db.compactMu.Unlock()
+
+	outID, sealed, live, err := db.prepareCompaction()
+	if err != nil || len(sealed) == 0 {
+		return err
+	}
+
+	out, moved, err := db.copyLiveValues(outID, sealed, live)
+	if err != nil {
+		return err
+	}
+
+	return db.finishCompaction(out, sealed, moved)
+}
+
+// prepareCompaction seals the active segment and takes a snapshot
+// of the key directory. The id reserved for the compacted segment sorts
+// after all sealed segments and before the new active segment,
+// so replaying segments in id order always yields the latest values.
+func (db *DB) prepareCompaction() (uint32, map[uint32]*segment, []movedValue, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return 0, nil, nil, ErrClosed
+	}
+
+	outID := db.nextID
+	db.nextID++
+
+	if err := db.rotate(); err != nil {
+		return 0, nil, nil, err
+	}
+
+	sealed := make(map[uint32]*segment, len(db.segments))
+
+	for id, seg := range db.segments {
+		if id < outID {
+			sealed[id] = seg
+		}
+	}
+
+	live := make([]movedValue, 0, db.keydir.Size())
+
+	db.keydir.ForEach(func(node art.Node) bool {
+		if ptr := node.Value().(valuePointer); ptr.segment < outID {
+			live = append(live, movedValue{key: node.Key(), oldPtr: ptr})
+		}
+
+		return true
+	})
+
+	return outID, sealed, live, nil
+}
+
+// copyLiveValues writes the live values into the segment with the given id.
+// Both the data and the hint files are written under temporary names
+// and renamed into place only when they are complete.
+func (db *DB) copyLiveValues(outID uint32, sealed map[uint32]*segment, live []movedValue) (*segment, []movedValue, error) {
+	out, err := db.createCompactedSegment(outID, sealed, live)
+	if err != nil {
+		os.Remove(segmentPath(db.dir, outID, dataFileExt+tempFileExt))
+
+		return nil, nil, err
+	}
+
+	if err := os.Rename(out.file.Name(), segmentPath(db.dir, outID, dataFileExt)); err != nil {
+		out.close()
+		os.Remove(out.file.Name())
+
+		return nil, nil, err
+	}
+
+	hints := make([]hint, 0, len(live))
+	for _, mv := range live {
+		hints = append(hints, hint{key: mv.key, ptr: mv.newPtr})
+	}
+
+	// without the hint file the segment is scanned on the next Open,
+	// so a failure here is not fatal for the data.
+	writeHintFile(db.dir, outID, hints) //nolint:errcheck
+
+	return out, live, nil
+}
+
+// createCompactedSegment copies the live values into a temporary data file.
+func (db *DB) createCompactedSegment(outID uint32, sealed map[uint32]*segment, live []movedValue) (*segment, error) {
+	file, err := os.OpenFile(segmentPath(db.dir, outID, dataFileExt+tempFileExt), os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0o644) //#nosec:G302,G304
+	if err != nil {
+		return nil, err
+	}
+
+	out := &segment{id: outID, file: file}
+
+	for i := range live {
+		// sealed segments are immutable and only compaction removes them,
+		// so they can be read without holding db.mu.
+		value, err := sealed[live[i].oldPtr.segment].read(live[i].oldPtr)
+		if err != nil {
+			out.close()
+
+			return nil, err
+		}
+
+		if live[i].newPtr, err = out.append(live[i].key, value, false); err != nil {
+			out.close()
+
+			return nil, err
+		}
+	}
+
+	if err := out.file.Sync(); err != nil {
+		out.close()
+
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// finishCompaction switches the leaf pointers to the compacted segment
+// and removes the sealed segments.
+func (db *DB) finishCompaction(out *segment, sealed map[uint32]*segment, moved []movedValue) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.segments[out.id] = out
+	db.total += out.size
+
+	for _, mv := range moved {
+		// skip keys that were updated or deleted while the values were copied.
+		if val, found := db.keydir.Search(mv.key); !found || val.(valuePointer) != mv.oldPtr {
+			continue
+		}
+
+		db.keydir.Insert(mv.key, mv.newPtr)
+		db.live += mv.newPtr.recordSize(len(mv.key)) - mv.oldPtr.recordSize(len(mv.key))
+	}
+
+	var err error
+
+	for _, seg := range sealed {
+		delete(db.segments, seg.id)
+		db.total -= seg.size
+
+		if closeErr := seg.close(); err == nil {
+			err = closeErr
+		}
+
+		if removeErr := removeSegmentFiles(db.dir, seg.id); err == nil {
+			err = removeErr
+		}
+	}
+
+	return err
+}
+
+// compactInBackground periodically compacts the database
+// when the share of dead bytes reaches the configured ratio.
+func (db *DB) compactInBackground() {
+	defer close(db.done)
+
+	ticker := time.NewTicker(db.opts.compactionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			if db.DeadRatio() >= db.opts.compactionRatio {
+				db.Compact() //nolint:errcheck
+			}
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package bitcask
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	dataFileExt = ".data" // segment data file extension.
+	hintFileExt = ".hint" // segment hint file extension.
+	tempFileExt = ".tmp"  // extension of files written by compaction before rename.
+
+	recordHeaderSize = 12 // crc32(4) + keyLen(4) + valueLen(4)
+	hintHeaderSize   = 16 // keyLen(4) + valueLen(4) + valueOffset(8)
+
+	// tombstoneLen is a special value length that marks a deleted key.
+	tombstoneLen = math.MaxUint32
+)
+
+// valuePointer is stored in the tree instead of the value itself.
+// It locates the value bytes inside a segment data file.
+type valuePointer struct {
+	segment uint32 // segment id
+	offset  int64  // offset of the value bytes in the data file
+	length  uint32 // length of the value
+}
+
+// recordSize returns the size of the whole record the value belongs to.
+func (vp valuePointer) recordSize(keyLen int) int64 {
+	return recordHeaderSize + int64(keyLen) + int64(vp.length)
+}
+
+// hint describes a single record of a segment.
+// Hints are persisted to the hint file when a segment is sealed,
+// so the key directory can be rebuilt without reading the values.
+type hint struct {
+	key       []byte
+	ptr       valuePointer
+	tombstone bool
+}
+
+// segmentFile is the data file of a segment, it is implemented by *os.File.
+type segmentFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Seeker
+	io.Closer
+	Truncate(size int64) error
+	Sync() error
+	Name() string
+}
+
+// segment is an append-only data file.
+type segment struct {
+	id   uint32
+	file segmentFile
+	size int64
+	err  error // sticky error of a partial write that could not be rolled back
+}
+
+// segmentPath returns the path of the segment file with the given extension.
+func segmentPath(dir string, id uint32, ext string) string {
+	return filepath.Join(dir, fmt.Sprintf("%010d%s", id, ext))
+}
+
+// openSegment opens or creates the segment data file.
+func openSegment(dir string, id uint32) (*segment, error) {
+	file, err := os.OpenFile(segmentPath(dir, id, dataFileExt), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644) //#nosec:G302
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return &segment{id: id, file: file, size: info.Size()}, nil
+}
+
+// append writes a record to the end of the segment.
+// A nil value with tombstone set to true writes a delete marker.
+// A partially written record is truncated, so the segment ends with the last complete record.
+// If the truncation fails, the segment rejects all further writes.
+func (s *segment) append(key, value []byte, tombstone bool) (valuePointer, error) {
+	if s.err != nil {
+		return valuePointer{}, s.err
+	}
+
+	valueLen := uint32(len(value)) //#nosec:G115
+	if tombstone {
+		valueLen = tombstoneLen
+	}
+
+	buf := make([]byte, recordHeaderSize+len(key)+len(value))
+	binary.BigEndian.PutUint32(buf[4:], uint32(len(key))) //#nosec:G115
+	binary.BigEndian.PutUint32(buf[8:], valueLen)
+	copy(buf[recordHeaderSize:], key)
+	copy(buf[recordHeaderSize+len(key):], value)
+	binary.BigEndian.PutUint32(buf[0:], crc32.ChecksumIEEE(buf[4:]))
+
+	if n, err := s.file.Write(buf); err != nil {
+		if n > 0 {
+			if truncErr := s.file.Truncate(s.size); truncErr != nil {
+				s.err = fmt.Errorf("%w: segment %d has a torn record: %v", err, s.id, truncErr) //nolint:errorlint
+
+				return valuePointer{}, s.err
+			}
+		}
+
+		return valuePointer{}, err
+	}
+
+	ptr := valuePointer{
+		segment: s.id,
+		offset:  s.size + recordHeaderSize + int64(len(key)),
+		length:  uint32(len(value)), //#nosec:G115
+	}
+	s.size += int64(len(buf))
+
+	return ptr, nil
+}
+
+// read returns the value bytes the pointer refers to.
+func (s *segment) read(ptr valuePointer) ([]byte, error) {
+	buf := make([]byte, ptr.length)
+	if _, err := s.file.ReadAt(buf, ptr.offset); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// close closes the segment data file.
+func (s *segment) close() error {
+	return s.file.Close()
+}
+
+// scan reads all records of the segment and returns them as hints.
+// A torn record at the end of the file (e.g. after a crash) is truncated.
+func (s *segment) scan() ([]hint, error) {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var (
+		hints  []hint
+		offset int64
+		header [recordHeaderSize]byte
+	)
+
+	reader := bufio.NewReader(s.file)
+
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return hints, nil
+			}
+
+			return hints, s.truncate(offset, err)
+		}
+
+		keyLen := binary.BigEndian.Uint32(header[4:])
+		valueLen := binary.BigEndian.Uint32(header[8:])
+
+		bodyLen := int64(keyLen)
+		if valueLen != tombstoneLen {
+			bodyLen += int64(valueLen)
+		}
+
+		if offset+recordHeaderSize+bodyLen > s.size {
+			return hints, s.truncate(offset, io.ErrUnexpectedEOF)
+		}
+
+		body := make([]byte, bodyLen)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return hints, s.truncate(offset, err)
+		}
+
+		crc := crc32.NewIEEE()
+		crc.Write(header[4:])
+		crc.Write(body)
+
+		if crc.Sum32() != binary.BigEndian.Uint32(header[0:]) {
+			return hints, s.truncate(offset, ErrCorrupted)
+		}
+
+		h := hint{
+			key: body[:keyLen],
+			ptr: valuePointer{
+				segment: s.id,
+				offset:  offset + recordHeaderSize + int64(keyLen),
+				length:  valueLen,
+			},
+			tombstone: valueLen == tombstoneLen,
+		}
+
+		if h.tombstone {
+			h.ptr.length = 0
+		}
+
+		hints = append(hints, h)
+		offset += recordHeaderSize + bodyLen
+	}
+}
+
+// truncate drops the torn tail of the segment starting at offset.
+func (s *segment) truncate(offset int64, cause error) error {
+	if err := s.file.Truncate(offset); err != nil {
+		return fmt.Errorf("%w: %v", cause, err) //nolint:errorlint
+	}
+
+	s.size = offset
+
+	return nil
+}
+
+// writeHintFile persists the hints of a sealed segment.
+// The file is written under a temporary name and renamed into place.
+func writeHintFile(dir string, id uint32, hints []hint) error {
+	path := segmentPath(dir, id, hintFileExt)
+	tmpPath := path + tempFileExt
+
+	file, err := os.Create(tmpPath) //#nosec:G304
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+
+	var header [hintHeaderSize]byte
+
+	for _, h := range hints {
+		valueLen := h.ptr.length
+		if h.tombstone {
+			valueLen = tombstoneLen
+		}
+
+		binary.BigEndian.PutUint32(header[0:], uint32(len(h.key))) //#nosec:G115
+		binary.BigEndian.PutUint32(header[4:], valueLen)
+		binary.BigEndian.PutUint64(header[8:], uint64(h.ptr.offset)) //#nosec:G115
+
+		if _, err = writer.Write(header[:]); err != nil {
+			break
+		}
+
+		if _, err = writer.Write(h.key); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpPath)
+
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// readHintFile loads the hints of the segment with the given id.
+func readHintFile(dir string, id uint32) ([]hint, error) {
+	file, err := os.Open(segmentPath(dir, id, hintFileExt))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		hints  []hint
+		header [hintHeaderSize]byte
+	)
+
+	reader := bufio.NewReader(file)
+
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return hints, nil
+			}
+
+			return nil, fmt.Errorf("%w: hint file of segment %d", ErrCorrupted, id)
+		}
+
+		key := make([]byte, binary.BigEndian.Uint32(header[0:]))
+		if _, err := io.ReadFull(reader, key); err != nil {
+			return nil, fmt.Errorf("%w: hint file of segment %d", ErrCorrupted, id)
+		}
+
+		valueLen := binary.BigEndian.Uint32(header[4:])
+		h := hint{
+			key: key,
+			ptr: valuePointer{
+				segment: id,
+				offset:  int64(binary.BigEndian.Uint64(header[8:])), //#nosec:G115
+				length:  valueLen,
+			},
+			tombstone: valueLen == tombstoneLen,
+		}
+
+		if h.tombstone {
+			h.ptr.length = 0
+		}
+
+		hints = append(hints, h)
+	}
+}
+
+// listSegments returns ids of all segment data files in ascending order.
+// Leftovers of an interrupted compaction are removed.
+func listSegments(dir string) ([]uint32, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint32, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if strings.HasSuffix(name, tempFileExt) {
+			os.Remove(filepath.Join(dir, name))
+
+			continue
+		}
+
+		if !strings.HasSuffix(name, dataFileExt) {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, dataFileExt), 10, 32)
+		if err != nil {
+			continue // not a segment file
+		}
+
+		ids = append(ids, uint32(id))
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
+
+// removeSegmentFiles deletes the data and hint files of the segment.
+func removeSegmentFiles(dir string, id uint32) error {
+	if err := os.Remove(segmentPath(dir, id, dataFileExt)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Remove(segmentPath(dir, id, hintFileExt)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+)
+
+// Input formats supported by the build command.
+const (
+	formatKeys = "keys"
+	formatTSV  = "tsv"
+)
+
+// errNotFound is returned by the search command if any key is missing.
+var errNotFound = errors.New("key not found")
+
+// newFlagSet creates a flag set for the subcommand.
+// Flag errors are reported by the returned error, the usage is printed to stdout.
+func newFlagSet(name, args string, stdout io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	fs.Usage = func() {
+		fmt.Fprintf(stdout, "Usage: art %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses the arguments and checks the number of positional arguments.
+// It returns flag.ErrHelp if the help was requested.
+// A negative maxArgs means there is no upper limit.
+func parseFlags(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return errUsage
+	}
+
+	if fs.NArg() < minArgs || maxArgs >= 0 && fs.NArg() > maxArgs {
+		fs.Usage()
+
+		return errUsage
+	}
+
+	return nil
+}
+
+// snapshotFlag registers the -f flag with the snapshot path.
+func snapshotFlag(fs *flag.FlagSet) *string {
+	return fs.String("f", "tree.art", "snapshot `file`")
+}
+
+// runBuild builds a tree from the input files and saves the snapshot.
+func runBuild(args []string, stdout io.Writer) error {
+	fs := newFlagSet("build", "file...", stdout)
+	output := fs.String("o", "tree.art", "output snapshot `file`")
+	format := fs.String("format", formatKeys, `input format: "keys" (one key per line) or "tsv" (key<TAB>value per line)`)
+
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	if *format != formatKeys && *format != formatTSV {
+		return fmt.Errorf("unknown input format %q", *format)
+	}
+
+	tree := art.New()
+
+	for _, path := range fs.Args() {
+		if err := loadFile(tree, path, *format); err != nil {
+			return err
+		}
+	}
+
+	if err := saveSnapshot(tree, *output); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d keys saved to %s\n", tree.Size(), *output)
+
+	return nil
+}
+
+// loadFile inserts the keys of the input file into the tree, "-" reads stdin.
+// Keys without values store the key as the value.
+func loadFile(tree art.Tree, path, format string) error {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		r = file
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value := line, line
+
+		if format == formatTSV {
+			if idx := strings.IndexByte(line, '\t'); idx >= 0 {
+				key, value = line[:idx], line[idx+1:]
+			}
+		}
+
+		tree.Insert(art.Key(key), value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// saveSnapshot writes the tree snapshot to the file.
+func saveSnapshot(tree art.Tree, path string) error {
+	data, err := art.MarshalTree(tree)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadSnapshot reads the tree snapshot from the file.
+func loadSnapshot(path string) (art.Tree, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := art.New()
+	if err := art.UnmarshalTree(tree, data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return tree, nil
+}
+
+// entry is a key/value pair, e.g. a leaf node or a cursor position.
+type entry interface {
+	Key() art.Key
+	Value() art.Value
+}
+
+// printLeaf prints the key/value pair of the leaf as a TSV line.
+func printLeaf(w io.Writer, leaf entry) {
+	fmt.Fprintf(w, "%s\t%v\n", leaf.Key(), leaf.Value())
+}
+
+// limitedPrinter returns a callback printing up to limit leaves, a non-positive limit prints all.
+func limitedPrinter(w io.Writer, limit int) art.Callback {
+	count := 0
+
+	return func(node art.Node) bool {
+		printLeaf(w, node)
+		count++
+
+		return limit <= 0 || count < limit
+	}
+}
+
+// traverseOptions returns the tree traversal options for the reverse flag.
+func traverseOptions(reverse bool) int {
+	if reverse {
+		return art.TraverseLeaf | art.TraverseReverse
+	}
+
+	return art.TraverseLeaf
+}
+
+// runSearch prints the values of the keys.
+func runSearch(args []string, stdout io.Writer) error {
+	fs := newFlagSet("search", "key...", stdout)
+	path := snapshotFlag(fs)
+
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+
+	for _, key := range fs.Args() {
+		value, found := tree.Search(art.Key(key))
+		if !found {
+			missing = append(missing, key)
+
+			continue
+		}
+
+		fmt.Fprintf(stdout, "%s\t%v\n", key, value)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", errNotFound, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// runExplain prints the nodes visited by the lookup of the keys.
+func runExplain(args []string, stdout io.Writer) error {
+	fs := newFlagSet("explain", "key...", stdout)
+	path := snapshotFlag(fs)
+
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range fs.Args() {
+		exp, err := art.Explain(tree, art.Key(key))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(stdout, exp)
+	}
+
+	return nil
+}
+
+// runPrefix prints the key/value pairs with the given key prefix.
+func runPrefix(args []string, stdout io.Writer) error {
+	fs := newFlagSet("prefix", "prefix", stdout)
+	path := snapshotFlag(fs)
+	limit := fs.Int("limit", 0, "maximum number of keys to print, 0 prints all")
+	reverse := fs.Bool("reverse", false, "print keys in descending order")
+
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	tree.ForEachPrefix(art.Key(fs.Arg(0)), limitedPrinter(stdout, *limit), traverseOptions(*reverse))
+
+	return nil
+}
+
+// runRange prints the key/value pairs in the key range [start, end).
+// An empty start or end leaves that side of the range unbounded.
+func runRange(args []string, stdout io.Writer) error {
+	fs := newFlagSet("range", "start [end]", stdout)
+	path := snapshotFlag(fs)
+	limit := fs.Int("limit", 0, "maximum number of keys to print, 0 prints all")
+
+	if err := parseFlags(fs, args, 1, 2); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	start, end := art.Key(fs.Arg(0)), art.Key(fs.Arg(1))
+	cursor, err := art.NewCursor(tree)
+	if err != nil {
+		return err
+	}
+
+	for count, ok := 0, cursor.Seek(start); ok; ok = cursor.Next() {
+		if len(end) > 0 && bytes.Compare(cursor.Key(), end) >= 0 {
+			break
+		}
+
+		printLeaf(stdout, cursor)
+
+		if count++; *limit > 0 && count >= *limit {
+			break
+		}
+	}
+
+	return nil
+}
+
+// runStats prints the tree statistics.
+func runStats(args []string, stdout io.Writer) error {
+	fs := newFlagSet("stats", "", stdout)
+	path := snapshotFlag(fs)
+	byPrefix := fs.Int("by-prefix", 0, "print memory usage aggregated by key prefixes of the given `length`")
+	limit := fs.Int("limit", 20, "maximum number of prefixes to print, 0 prints all")
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	stats, err := art.Stats(tree)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "keys\t%d\n", tree.Size())
+
+	for _, kind := range []art.Kind{art.Leaf, art.Node4, art.Node16, art.Node48, art.Node256} {
+		fmt.Fprintf(stdout, "%s\t%d", kind, stats.Nodes[kind])
+
+		if kind != art.Leaf {
+			fmt.Fprintf(stdout, "\tavg fanout %.2f", stats.AvgFanout[kind])
+		}
+
+		fmt.Fprintln(stdout)
+	}
+
+	fmt.Fprintf(stdout, "depth\tmin %d, avg %.2f, max %d\n", stats.MinDepth, stats.AvgDepth, stats.MaxDepth)
+	fmt.Fprintf(stdout, "bytes\tnodes %d, keys %d, prefixes %d, total %d\n",
+		stats.NodeBytes, stats.KeyBytes, stats.PrefixBytes, stats.TotalBytes)
+
+	if *byPrefix > 0 {
+		prefixes, err := art.MemoryByPrefix(tree, *byPrefix)
+		if err != nil {
+			return err
+		}
+
+		for i, pm := range prefixes {
+			if *limit > 0 && i >= *limit {
+				break
+			}
+
+			fmt.Fprintf(stdout, "prefix\t%q\tbytes %d, keys %d\n", pm.Prefix, pm.Bytes, pm.Leaves)
+		}
+	}
+
+	return nil
+}
+
+// runVerify checks the tree invariants.
+func runVerify(args []string, stdout io.Writer) error {
+	fs := newFlagSet("verify", "", stdout)
+	path := snapshotFlag(fs)
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	if err := art.Verify(tree); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d keys verified\n", tree.Size())
+
+	return nil
+}
+
+// runDump prints the tree structure.
+func runDump(args []string, stdout io.Writer) error {
+	fs := newFlagSet("dump", "", stdout)
+	path := snapshotFlag(fs)
+	prefix := fs.String("prefix", "", "print only the subtree with the key `prefix`")
+	depth := fs.Int("depth", 0, "maximum depth of the printed nodes, 0 prints all")
+	maxChildren := fs.Int("max-children", 0, "maximum number of children printed per node, 0 prints all child slots")
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(stdout, art.TreeStringer(tree,
+		art.WithPrefix(art.Key(*prefix)),
+		art.WithMaxDepth(*depth),
+		art.WithMaxChildren(*maxChildren),
+	))
+
+	return nil
+}
+
+// runDOT prints the tree structure as a Graphviz DOT graph.
+func runDOT(args []string, stdout io.Writer) error {
+	fs := newFlagSet("dot", "", stdout)
+	path := snapshotFlag(fs)
+	prefix := fs.String("prefix", "", "print only the subtree with the key `prefix`")
+	depth := fs.Int("depth", 0, "maximum depth of the printed nodes, 0 prints all")
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	return art.WriteDOT(stdout, tree, art.WithPrefix(art.Key(*prefix)), art.WithMaxDepth(*depth))
+}
+
+// runExport prints all key/value pairs as TSV lines in ascending key order.
+func runExport(args []string, stdout io.Writer) error {
+	fs := newFlagSet("export", "", stdout)
+	path := snapshotFlag(fs)
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	tree, err := loadSnapshot(*path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	tree.ForEach(func(node art.Node) bool {
+		printLeaf(w, node)
+
+		return true
+	})
+
+	return w.Flush()
+}
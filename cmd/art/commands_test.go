@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCmd runs the tool with the arguments and returns its stdout.
+func runCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	err := run(args, &stdout, &stderr)
+
+	return stdout.String(), err
+}
+
+// buildSnapshot builds a snapshot from the lines and returns its path.
+func buildSnapshot(t *testing.T, format string, lines ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.txt")
+	require.NoError(t, os.WriteFile(input, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+	snapshot := filepath.Join(dir, "tree.art")
+	out, err := runCmd(t, "build", "-format", format, "-o", snapshot, input)
+	require.NoError(t, err)
+	assert.Equal(t, "4 keys saved to "+snapshot+"\n", out)
+
+	return snapshot
+}
+
+func TestBuildAndQueryKeys(t *testing.T) {
+	t.Parallel()
+
+	snapshot := buildSnapshot(t, formatKeys, "apple", "apricot", "banana", "apple", "app")
+
+	out, err := runCmd(t, "search", "-f", snapshot, "apple", "banana")
+	require.NoError(t, err)
+	assert.Equal(t, "apple\tapple\nbanana\tbanana\n", out)
+
+	out, err = runCmd(t, "search", "-f", snapshot, "apple", "cherry")
+	require.ErrorIs(t, err, errNotFound)
+	assert.Equal(t, "apple\tapple\n", out)
+
+	out, err = runCmd(t, "explain", "-f", snapshot, "apple")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, `search "apple": found`))
+
+	out, err = runCmd(t, "prefix", "-f", snapshot, "ap")
+	require.NoError(t, err)
+	assert.Equal(t, "app\tapp\napple\tapple\napricot\tapricot\n", out)
+
+	out, err = runCmd(t, "prefix", "-f", snapshot, "-reverse", "-limit", "2", "ap")
+	require.NoError(t, err)
+	assert.Equal(t, "apricot\tapricot\napple\tapple\n", out)
+
+	out, err = runCmd(t, "range", "-f", snapshot, "apple", "b")
+	require.NoError(t, err)
+	assert.Equal(t, "apple\tapple\napricot\tapricot\n", out)
+
+	out, err = runCmd(t, "range", "-f", snapshot, "apricot")
+	require.NoError(t, err)
+	assert.Equal(t, "apricot\tapricot\nbanana\tbanana\n", out)
+
+	out, err = runCmd(t, "range", "-f", snapshot, "-limit", "2", "applf")
+	require.NoError(t, err)
+	assert.Equal(t, "apricot\tapricot\nbanana\tbanana\n", out)
+
+	out, err = runCmd(t, "range", "-f", snapshot, "-limit", "2", "a", "c")
+	require.NoError(t, err)
+	assert.Equal(t, "app\tapp\napple\tapple\n", out)
+
+	out, err = runCmd(t, "range", "-f", snapshot, "c")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = runCmd(t, "stats", "-f", snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, out, "keys\t4\nLeaf\t4\nNode4\t3\tavg fanout 2.00\nNode16\t0\tavg fanout 0.00\n")
+	assert.Contains(t, out, "depth\tmin 1, avg 2.25, max 3\n")
+	assert.Contains(t, out, "keys 21, prefixes 1")
+	assert.NotContains(t, out, "prefix\t")
+
+	out, err = runCmd(t, "stats", "-f", snapshot, "-by-prefix", "2", "-limit", "2")
+	require.NoError(t, err)
+	assert.Contains(t, out, "\nprefix\t\"ap\"\t")
+	assert.Equal(t, 2, strings.Count(out, "\nprefix\t"))
+
+	out, err = runCmd(t, "verify", "-f", snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, "4 keys verified\n", out)
+
+	out, err = runCmd(t, "dump", "-f", snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Node4")
+	assert.Contains(t, out, "val: apricot")
+
+	out, err = runCmd(t, "dump", "-f", snapshot, "-prefix", "ban", "-max-children", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "─── Leaf (#0)\n    key(6): [banana] [98 97 110 97 110 97]\n    val: banana\n", out)
+
+	out, err = runCmd(t, "dot", "-f", snapshot, "-prefix", "app")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "digraph art {"))
+	assert.Contains(t, out, `key: \"apple\"`)
+	assert.NotContains(t, out, "apricot")
+}
+
+func TestBuildAndExportTSV(t *testing.T) {
+	t.Parallel()
+
+	snapshot := buildSnapshot(t, formatTSV, "b\t2", "a\t1", "c", "d\tx\ty")
+
+	out, err := runCmd(t, "export", "-f", snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, "a\t1\nb\t2\nc\tc\nd\tx\ty\n", out)
+}
+
+func TestUsageErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := runCmd(t)
+	require.ErrorIs(t, err, errUsage)
+
+	_, err = runCmd(t, "unknown")
+	require.ErrorIs(t, err, errUsage)
+
+	_, err = runCmd(t, "search")
+	require.ErrorIs(t, err, errUsage)
+
+	_, err = runCmd(t, "build", "-unknown-flag", "file")
+	require.ErrorIs(t, err, errUsage)
+
+	out, err := runCmd(t, "prefix", "-h")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Usage: art prefix")
+
+	_, err = runCmd(t, "build", "-format", "csv", "file")
+	require.Error(t, err)
+
+	_, err = runCmd(t, "stats", "-f", filepath.Join(t.TempDir(), "missing.art"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadInvalidSnapshot(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "invalid.art")
+	require.NoError(t, os.WriteFile(path, []byte("not a snapshot"), 0o600))
+
+	_, err := runCmd(t, "stats", "-f", path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path)
+}
@@ -0,0 +1,108 @@
+// Command art builds, saves and inspects Adaptive Radix Tree snapshots.
+//
+// A tree is built from newline-delimited keys or tab-separated key/value files
+// and saved in the library's binary snapshot format:
+//
+//	art build -o words.art test/assets/words.txt
+//	art build -format tsv -o dict.art dict.tsv
+//
+// The saved snapshot can be queried from the shell:
+//
+//	art search -f words.art apple banana
+//	art explain -f words.art apple
+//	art prefix -f words.art -limit 10 antisa
+//	art range -f words.art apple apricot
+//	art stats -f words.art
+//	art verify -f words.art
+//	art dump -f words.art
+//	art dot -f words.art -prefix antisa | dot -Tsvg > antisa.svg
+//	art export -f words.art > words.tsv
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// errUsage is returned when the command line arguments are invalid.
+var errUsage = errors.New("invalid arguments")
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) error
+}
+
+// commands returns all subcommands in the order they are listed in the usage.
+func commands() []command {
+	return []command{
+		{"build", "build a snapshot from key or TSV files", runBuild},
+		{"search", "print values of the given keys", runSearch},
+		{"explain", "print the nodes visited by the lookup of the given keys", runExplain},
+		{"prefix", "print key/value pairs with the given key prefix", runPrefix},
+		{"range", "print key/value pairs in the key range [start, end)", runRange},
+		{"stats", "print tree statistics and memory usage", runStats},
+		{"verify", "check the tree invariants", runVerify},
+		{"dump", "print the tree structure", runDump},
+		{"dot", "print the tree structure as a Graphviz DOT graph", runDOT},
+		{"export", "print all key/value pairs as TSV", runExport},
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "art:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// run executes the subcommand given by the first argument.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+
+		return errUsage
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:], stdout); !errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+
+			return nil
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stdout)
+
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "art: unknown command %q\n", args[0])
+	usage(stderr)
+
+	return errUsage
+}
+
+// usage prints the list of subcommands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: art <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "art <command> -h" for the command flags.`)
+}
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"errors"
-	"fmt"
-
 	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/plar/go-adaptive-radix-tree/v2/keys"
 )
 
 // GTree is a generic tree that supports any type for keys and values.
@@ -73,12 +71,9 @@ func (gt *GTree[K, V]) ForEach(callback func(node art.Node) bool, options ...int
 	gt.tree.ForEach(callback, options...)
 }
 
-// Helper function to convert an integer key to a byte slice.
+// Helper function to convert a key to a byte slice.
+// The order-preserving encoding keeps the keys sorted by their natural order,
+// e.g. -1 < 9 < 10, see the keys package for the supported types.
 func convertKeyToBytes[K comparable](key K) ([]byte, error) {
-	switch v := any(key).(type) {
-	case int:
-		return []byte(fmt.Sprintf("%d", v)), nil // Simple conversion
-	default:
-		return nil, errors.New("unsupported key type")
-	}
+	return keys.AppendTuple(nil, key)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/plar/go-adaptive-radix-tree/v2/keys"
 )
 
 func main() {
@@ -14,6 +15,8 @@ func main() {
 	tree.Insert(1, "one")
 	tree.Insert(2, "two")
 	tree.Insert(3, "three")
+	tree.Insert(10, "ten")
+	tree.Insert(-1, "minus one")
 
 	// Search for a value.
 	if value, found := tree.Search(2); found {
@@ -30,11 +33,12 @@ func main() {
 	}
 
 	// Check the size of the tree.
-	fmt.Printf("Tree Size: %d\n", tree.Size()) // Output: Tree Size: 2
+	fmt.Printf("Tree Size: %d\n", tree.Size()) // Output: Tree Size: 4
 
-	// Traverse the tree using ForEach.
+	// Traverse the tree using ForEach, the keys are visited in the numeric order.
 	tree.ForEach(func(node art.Node) bool {
-		fmt.Printf("Node Key: %s, Node Value: %s\n", string(node.Key()), node.Value().(string))
+		key, _, _ := keys.DecodeInt64(node.Key())
+		fmt.Printf("Node Key: %d, Node Value: %s\n", key, node.Value().(string))
 		return true // Continue iteration
 	}, art.TraverseLeaf)
 }
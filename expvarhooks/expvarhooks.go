@@ -0,0 +1,57 @@
+// Package expvarhooks publishes the structural events of an Adaptive Radix Tree
+// as expvar counters.
+//
+// The counters are grouped in an expvar.Map:
+//
+//	tree := art.NewWithOptions(art.WithHooks(expvarhooks.New("art")))
+//
+// With the expvar handler registered, /debug/vars shows the counters under "art":
+// "grow.Node4.Node16", "shrink.Node16.Node4", "split_leaf", "split_node", etc.
+package expvarhooks
+
+import (
+	"expvar"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+)
+
+// Counter names for the split events.
+const (
+	SplitLeaf = "split_leaf"
+	SplitNode = "split_node"
+)
+
+// New returns hooks that count the tree events in a new expvar.Map published under the name.
+// It panics if the name is already registered, see expvar.Publish.
+func New(name string) *art.Hooks {
+	return ForMap(expvar.NewMap(name))
+}
+
+// ForMap returns hooks that count the tree events in the given map.
+// The map can be shared by several trees.
+func ForMap(m *expvar.Map) *art.Hooks {
+	return &art.Hooks{
+		OnGrow: func(from, to art.Kind) {
+			m.Add(GrowKey(from, to), 1)
+		},
+		OnShrink: func(from, to art.Kind) {
+			m.Add(ShrinkKey(from, to), 1)
+		},
+		OnSplitLeaf: func(int) {
+			m.Add(SplitLeaf, 1)
+		},
+		OnSplitNode: func(int) {
+			m.Add(SplitNode, 1)
+		},
+	}
+}
+
+// GrowKey returns the counter name for the node growth from one kind to another.
+func GrowKey(from, to art.Kind) string {
+	return "grow." + from.String() + "." + to.String()
+}
+
+// ShrinkKey returns the counter name for the node shrinking from one kind to another.
+func ShrinkKey(from, to art.Kind) string {
+	return "shrink." + from.String() + "." + to.String()
+}
@@ -0,0 +1,58 @@
+package expvarhooks
+
+import (
+	"expvar"
+	"testing"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter returns the value of the counter in the map, 0 if it is missing.
+func counter(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+
+	return 0
+}
+
+func TestForMap(t *testing.T) {
+	t.Parallel()
+
+	m := new(expvar.Map).Init()
+	tree := art.NewWithOptions(art.WithHooks(ForMap(m)))
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(art.Key{'a', 'b', 'c', byte(i)}, i)
+	}
+
+	tree.Insert(art.Key("abx"), "x")
+
+	for i := 0; i < 256; i++ {
+		tree.Delete(art.Key{'a', 'b', 'c', byte(i)})
+	}
+
+	assert.Equal(t, int64(1), counter(m, GrowKey(art.Node4, art.Node16)))
+	assert.Equal(t, int64(1), counter(m, GrowKey(art.Node16, art.Node48)))
+	assert.Equal(t, int64(1), counter(m, GrowKey(art.Node48, art.Node256)))
+	assert.Equal(t, int64(1), counter(m, ShrinkKey(art.Node256, art.Node48)))
+	assert.Equal(t, int64(1), counter(m, ShrinkKey(art.Node48, art.Node16)))
+	assert.Equal(t, int64(1), counter(m, ShrinkKey(art.Node16, art.Node4)))
+	assert.Equal(t, int64(1), counter(m, SplitLeaf))
+	assert.Equal(t, int64(1), counter(m, SplitNode))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tree := art.NewWithOptions(art.WithHooks(New("expvarhooks_test")))
+	tree.Insert(art.Key("a"), 1)
+	tree.Insert(art.Key("b"), 2)
+
+	m, ok := expvar.Get("expvarhooks_test").(*expvar.Map)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), counter(m, SplitLeaf))
+	assert.Contains(t, m.String(), `"split_leaf": 1`)
+}
@@ -0,0 +1,177 @@
+package fst
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// builderArc is a transition of a state under construction.
+type builderArc struct {
+	label  byte
+	output uint64
+	target uint32 // compiled target state, the last arc's target is not compiled yet
+}
+
+// builderNode is a state under construction.
+type builderNode struct {
+	final       bool
+	finalOutput uint64
+	arcs        []builderArc
+}
+
+// builder constructs a minimal FST from sorted keys in one pass
+// (Daciuk et al. "Incremental construction of minimal acyclic finite-state automata",
+// extended with outputs as described by Mihov and Maurel).
+//
+// The nodes for the last inserted key are kept on the unfinished stack.
+// When the next key arrives, the nodes past the common prefix can no longer change,
+// so they are compiled and deduplicated by the registry of equivalent states.
+type builder struct {
+	fst        *FST
+	unfinished []*builderNode
+	registry   map[string]uint32
+	lastKey    []byte
+	hasLast    bool
+	sig        bytes.Buffer
+}
+
+func newBuilder() *builder {
+	return &builder{
+		fst:        &FST{},
+		unfinished: []*builderNode{{}},
+		registry:   make(map[string]uint32),
+	}
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b []byte) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+// insert adds the next key. Keys must be inserted in ascending order.
+func (b *builder) insert(key []byte, output uint64) error {
+	prefixLen := commonPrefix(b.lastKey, key)
+	if b.hasLast && (prefixLen == len(key) || (prefixLen < len(b.lastKey) && b.lastKey[prefixLen] > key[prefixLen])) {
+		return ErrUnsortedKeys
+	}
+
+	// the nodes past the common prefix are complete.
+	b.compileFrom(prefixLen)
+
+	// move the outputs shared with the previous key towards the root.
+	for i := 0; i < prefixLen; i++ {
+		node := b.unfinished[i]
+		last := &node.arcs[len(node.arcs)-1]
+
+		common := minUint64(last.output, output)
+		if suffix := last.output - common; suffix > 0 {
+			b.unfinished[i+1].prependOutput(suffix)
+		}
+
+		last.output = common
+		output -= common
+	}
+
+	// add the nodes for the new suffix, the rest of the output goes to the first new arc.
+	for i := prefixLen; i < len(key); i++ {
+		b.unfinished[i].arcs = append(b.unfinished[i].arcs, builderArc{label: key[i], output: output})
+		b.unfinished = append(b.unfinished, &builderNode{})
+		output = 0
+	}
+
+	last := b.unfinished[len(key)]
+	last.final = true
+	last.finalOutput = output
+
+	b.lastKey = append(b.lastKey[:0], key...)
+	b.hasLast = true
+	b.fst.size++
+
+	return nil
+}
+
+// prependOutput adds the output to all outgoing transitions and to the final output.
+func (n *builderNode) prependOutput(output uint64) {
+	for i := range n.arcs {
+		n.arcs[i].output += output
+	}
+
+	if n.final {
+		n.finalOutput += output
+	}
+}
+
+// compileFrom compiles the unfinished nodes deeper than depth
+// and links them to their parents.
+func (b *builder) compileFrom(depth int) {
+	for len(b.unfinished)-1 > depth {
+		node := b.unfinished[len(b.unfinished)-1]
+		b.unfinished = b.unfinished[:len(b.unfinished)-1]
+
+		parent := b.unfinished[len(b.unfinished)-1]
+		parent.arcs[len(parent.arcs)-1].target = b.compile(node)
+	}
+}
+
+// compile returns the id of the state equivalent to the node,
+// registering a new state if there is none.
+func (b *builder) compile(node *builderNode) uint32 {
+	b.sig.Reset()
+
+	var buf [binary.MaxVarintLen64]byte
+
+	if node.final {
+		b.sig.WriteByte(1)
+		b.sig.Write(buf[:binary.PutUvarint(buf[:], node.finalOutput)])
+	} else {
+		b.sig.WriteByte(0)
+	}
+
+	for _, a := range node.arcs {
+		b.sig.WriteByte(a.label)
+		b.sig.Write(buf[:binary.PutUvarint(buf[:], a.output)])
+		b.sig.Write(buf[:binary.PutUvarint(buf[:], uint64(a.target))])
+	}
+
+	if id, ok := b.registry[b.sig.String()]; ok {
+		return id
+	}
+
+	id := uint32(len(b.fst.states)) //#nosec:G115
+	b.fst.states = append(b.fst.states, state{
+		final:       node.final,
+		finalOutput: node.finalOutput,
+		arcStart:    uint32(len(b.fst.arcs)), //#nosec:G115
+		arcCount:    uint16(len(node.arcs)),  //#nosec:G115
+	})
+
+	for _, a := range node.arcs {
+		b.fst.arcs = append(b.fst.arcs, arc{label: a.label, output: a.output, target: a.target})
+	}
+
+	b.registry[b.sig.String()] = id
+
+	return id
+}
+
+// finish compiles the remaining nodes and returns the FST.
+func (b *builder) finish() *FST {
+	b.compileFrom(0)
+	b.fst.root = b.compile(b.unfinished[0])
+	b.registry = nil
+
+	return b.fst
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,239 @@
+// Package fst compiles an Adaptive Radix Tree into a minimal acyclic
+// finite-state transducer (FST).
+//
+// An FST shares both common prefixes and common suffixes of the keys,
+// so static dictionaries take a fraction of the memory required by the tree.
+// The outputs (values) are restricted to uint64, they are distributed over
+// the transitions and summed up while a key is looked up.
+//
+// The FST is read-only. It is built from the sorted tree iterator in one pass:
+//
+//	tree := art.New()
+//	tree.Insert(art.Key("mop"), uint64(0))
+//	tree.Insert(art.Key("moth"), uint64(1))
+//	tree.Insert(art.Key("pop"), uint64(2))
+//
+//	f, err := fst.Build(tree)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	value, found := f.Search([]byte("moth")) // 1, true
+package fst
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+)
+
+// These errors can be returned when building an FST.
+var (
+	ErrUnsupportedValue = errors.New("fst: value must be a non-negative integer")
+	ErrUnsortedKeys     = errors.New("fst: keys must be unique and sorted in ascending order")
+)
+
+// state is a compiled state of the transducer.
+// Its outgoing transitions are arcs[arcStart:arcStart+arcCount] sorted by label.
+type state struct {
+	finalOutput uint64
+	arcStart    uint32
+	arcCount    uint16
+	final       bool
+}
+
+// arc is a labeled transition to the target state.
+type arc struct {
+	output uint64
+	target uint32
+	label  byte
+}
+
+// FST is a minimal acyclic finite-state transducer mapping keys to uint64 values.
+type FST struct {
+	states []state
+	arcs   []arc
+	root   uint32
+	size   int
+}
+
+// Build compiles the tree into an FST. All tree values must be non-negative integers.
+func Build(t art.Tree) (*FST, error) {
+	return BuildFromIterator(t.Iterator())
+}
+
+// BuildFromIterator compiles the leaves returned by the iterator into an FST.
+// The iterator must return keys in ascending order, as the tree's default Iterator does.
+func BuildFromIterator(it art.Iterator) (*FST, error) {
+	b := newBuilder()
+
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if node.Kind() != art.Leaf {
+			continue
+		}
+
+		value, ok := toUint64(node.Value())
+		if !ok {
+			return nil, ErrUnsupportedValue
+		}
+
+		if err := b.insert(node.Key(), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.finish(), nil
+}
+
+// toUint64 converts integer values to uint64.
+func toUint64(v art.Value) (uint64, bool) {
+	switch v := v.(type) {
+	case uint64:
+		return v, true
+	case uint:
+		return uint64(v), true
+	case uint32:
+		return uint64(v), true
+	case uint16:
+		return uint64(v), true
+	case uint8:
+		return uint64(v), true
+	case int:
+		return uint64(v), v >= 0
+	case int64:
+		return uint64(v), v >= 0
+	case int32:
+		return uint64(v), v >= 0 //nolint:gosec
+	case int16:
+		return uint64(v), v >= 0 //nolint:gosec
+	case int8:
+		return uint64(v), v >= 0 //nolint:gosec
+	default:
+		return 0, false
+	}
+}
+
+// Len returns the number of keys in the FST.
+func (f *FST) Len() int {
+	return f.size
+}
+
+// NumStates returns the number of states of the FST.
+func (f *FST) NumStates() int {
+	return len(f.states)
+}
+
+// NumArcs returns the number of transitions of the FST.
+func (f *FST) NumArcs() int {
+	return len(f.arcs)
+}
+
+// arcsOf returns the outgoing transitions of the state.
+func (f *FST) arcsOf(s *state) []arc {
+	return f.arcs[s.arcStart : s.arcStart+uint32(s.arcCount)]
+}
+
+// findArc returns the transition with the given label.
+func (f *FST) findArc(s *state, label byte) (*arc, bool) {
+	arcs := f.arcsOf(s)
+
+	idx := sort.Search(len(arcs), func(i int) bool { return arcs[i].label >= label })
+	if idx < len(arcs) && arcs[idx].label == label {
+		return &arcs[idx], true
+	}
+
+	return nil, false
+}
+
+// Search returns the value associated with the key.
+func (f *FST) Search(key []byte) (uint64, bool) {
+	if f.size == 0 {
+		return 0, false
+	}
+
+	var output uint64
+
+	s := &f.states[f.root]
+	for _, ch := range key {
+		a, ok := f.findArc(s, ch)
+		if !ok {
+			return 0, false
+		}
+
+		output += a.output
+		s = &f.states[a.target]
+	}
+
+	if !s.final {
+		return 0, false
+	}
+
+	return output + s.finalOutput, true
+}
+
+// ForEach calls fn for every key in ascending order.
+// The iteration stops if fn returns false.
+// The key passed to fn is only valid until fn returns.
+func (f *FST) ForEach(fn func(key []byte, value uint64) bool) {
+	f.ForEachRange(nil, nil, fn)
+}
+
+// ForEachPrefix calls fn in ascending order for every key starting with the prefix.
+// The iteration stops if fn returns false.
+// The key passed to fn is only valid until fn returns.
+func (f *FST) ForEachPrefix(prefix []byte, fn func(key []byte, value uint64) bool) {
+	if f.size == 0 {
+		return
+	}
+
+	var output uint64
+
+	s := &f.states[f.root]
+	for _, ch := range prefix {
+		a, ok := f.findArc(s, ch)
+		if !ok {
+			return
+		}
+
+		output += a.output
+		s = &f.states[a.target]
+	}
+
+	it := f.newIterator(s, output, prefix)
+	for it.next() {
+		if !fn(it.key, it.value) {
+			return
+		}
+	}
+}
+
+// ForEachRange calls fn in ascending order for every key in the range [start, end).
+// A nil start means the range begins with the smallest key,
+// a nil end means the range includes the largest key.
+// The iteration stops if fn returns false.
+// The key passed to fn is only valid until fn returns.
+func (f *FST) ForEachRange(start, end []byte, fn func(key []byte, value uint64) bool) {
+	if f.size == 0 {
+		return
+	}
+
+	it := f.newIterator(&f.states[f.root], 0, nil)
+	it.seek(start)
+
+	for it.next() {
+		if end != nil && bytes.Compare(it.key, end) >= 0 {
+			return
+		}
+
+		if !fn(it.key, it.value) {
+			return
+		}
+	}
+}
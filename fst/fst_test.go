@@ -0,0 +1,212 @@
+package fst
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	key   string
+	value uint64
+}
+
+func loadTree(t *testing.T, path string) (art.Tree, [][]byte) {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	tree := art.New()
+
+	var words [][]byte
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := append([]byte(nil), scanner.Bytes()...)
+		if _, updated := tree.Insert(word, uint64(len(words))); !updated {
+			words = append(words, word)
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return tree, words
+}
+
+func collect(f *FST, iterate func(f *FST, fn func([]byte, uint64) bool)) []entry {
+	var entries []entry
+
+	iterate(f, func(key []byte, value uint64) bool {
+		entries = append(entries, entry{string(key), value})
+
+		return true
+	})
+
+	return entries
+}
+
+func TestBuildAndSearch(t *testing.T) {
+	t.Parallel()
+
+	tree := art.New()
+	tree.Insert(art.Key("mop"), uint64(10))
+	tree.Insert(art.Key("moth"), 5)
+	tree.Insert(art.Key("pop"), uint(7))
+	tree.Insert(art.Key("star"), int64(0))
+	tree.Insert(art.Key("stop"), uint8(3))
+	tree.Insert(art.Key("top"), int32(3))
+	tree.Insert(art.Key("mo"), uint16(1))
+
+	f, err := Build(tree)
+	require.NoError(t, err)
+	assert.Equal(t, 7, f.Len())
+
+	tree.ForEach(func(node art.Node) bool {
+		value, found := f.Search(node.Key())
+		assert.True(t, found, string(node.Key()))
+
+		expected, _ := toUint64(node.Value())
+		assert.Equal(t, expected, value, string(node.Key()))
+
+		return true
+	})
+
+	for _, key := range []string{"", "m", "mops", "st", "x", "topp"} {
+		_, found := f.Search([]byte(key))
+		assert.False(t, found, key)
+	}
+}
+
+func TestBuildEmptyKeyAndEmptyTree(t *testing.T) {
+	t.Parallel()
+
+	f, err := Build(art.New())
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.Len())
+
+	_, found := f.Search(nil)
+	assert.False(t, found)
+	assert.Empty(t, collect(f, (*FST).ForEach))
+
+	tree := art.New()
+	tree.Insert(art.Key(""), 1)
+	tree.Insert(art.Key("a"), 2)
+
+	f, err = Build(tree)
+	require.NoError(t, err)
+
+	value, found := f.Search(nil)
+	assert.True(t, found)
+	assert.Equal(t, uint64(1), value)
+	assert.Equal(t, []entry{{"", 1}, {"a", 2}}, collect(f, (*FST).ForEach))
+}
+
+func TestBuildErrors(t *testing.T) {
+	t.Parallel()
+
+	tree := art.New()
+	tree.Insert(art.Key("a"), "string")
+
+	_, err := Build(tree)
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+
+	tree.Insert(art.Key("a"), -1)
+
+	_, err = Build(tree)
+	assert.ErrorIs(t, err, ErrUnsupportedValue)
+
+	tree.Insert(art.Key("a"), 1)
+	tree.Insert(art.Key("b"), 2)
+
+	_, err = BuildFromIterator(tree.Iterator(art.TraverseReverse))
+	assert.ErrorIs(t, err, ErrUnsortedKeys)
+}
+
+func TestIteration(t *testing.T) {
+	t.Parallel()
+
+	tree := art.New()
+	for i, key := range []string{"a", "ab", "abc", "abd", "b", "ba", "bb", "c"} {
+		tree.Insert(art.Key(key), i)
+	}
+
+	f, err := Build(tree)
+	require.NoError(t, err)
+
+	all := []entry{{"a", 0}, {"ab", 1}, {"abc", 2}, {"abd", 3}, {"b", 4}, {"ba", 5}, {"bb", 6}, {"c", 7}}
+	assert.Equal(t, all, collect(f, (*FST).ForEach))
+
+	prefix := func(p string) func(*FST, func([]byte, uint64) bool) {
+		return func(f *FST, fn func([]byte, uint64) bool) { f.ForEachPrefix([]byte(p), fn) }
+	}
+
+	assert.Equal(t, all[1:4], collect(f, prefix("ab")))
+	assert.Equal(t, all[4:7], collect(f, prefix("b")))
+	assert.Equal(t, all, collect(f, prefix("")))
+	assert.Empty(t, collect(f, prefix("abz")))
+
+	rng := func(start, end []byte) func(*FST, func([]byte, uint64) bool) {
+		return func(f *FST, fn func([]byte, uint64) bool) { f.ForEachRange(start, end, fn) }
+	}
+
+	assert.Equal(t, all, collect(f, rng(nil, nil)))
+	assert.Equal(t, all[1:5], collect(f, rng([]byte("ab"), []byte("ba"))))
+	assert.Equal(t, all[2:5], collect(f, rng([]byte("abb"), []byte("b\x00"))))
+	assert.Equal(t, all[7:], collect(f, rng([]byte("bc"), nil)))
+	assert.Equal(t, all[:1], collect(f, rng(nil, []byte("a\x00"))))
+	assert.Empty(t, collect(f, rng([]byte("d"), nil)))
+
+	var count int
+
+	f.ForEach(func([]byte, uint64) bool {
+		count++
+
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+}
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"../test/assets/words.txt", "../test/assets/hsk_words.txt"} {
+		tree, words := loadTree(t, path)
+
+		f, err := Build(tree)
+		require.NoError(t, err)
+		assert.Equal(t, tree.Size(), f.Len())
+
+		for i, w := range words {
+			value, found := f.Search(w)
+			require.True(t, found, string(w))
+			assert.Equal(t, uint64(i), value)
+		}
+
+		// shared prefixes and suffixes need fewer states than the tree has leaves
+		assert.Less(t, f.NumStates(), tree.Size(), path)
+
+		var prev []byte
+
+		it := tree.Iterator()
+		f.ForEach(func(key []byte, value uint64) bool {
+			node, err := it.Next()
+			require.NoError(t, err)
+			assert.Equal(t, []byte(node.Key()), key)
+			assert.Equal(t, node.Value(), value)
+			assert.Negative(t, bytes.Compare(prev, key))
+
+			prev = append(prev[:0], key...)
+
+			return true
+		})
+		assert.False(t, it.HasNext())
+	}
+}
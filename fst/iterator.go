@@ -0,0 +1,91 @@
+package fst
+
+import "sort"
+
+// frame is a state visited by the iterator.
+type frame struct {
+	s         *state
+	output    uint64 // output accumulated on the way to the state
+	nextArc   int    // index of the next transition to follow
+	emitFinal bool   // true if the final output of the state is not visited yet
+}
+
+// iterator walks the FST in depth-first order,
+// which yields the keys in ascending order.
+type iterator struct {
+	f     *FST
+	stack []frame
+	key   []byte // key of the state on top of the stack
+	value uint64 // value of the last visited key
+}
+
+// newIterator creates an iterator over the keys reachable from the state.
+// The prefix and output are the key and output accumulated on the way to the state.
+func (f *FST) newIterator(s *state, output uint64, prefix []byte) *iterator {
+	return &iterator{
+		f:     f,
+		stack: []frame{{s: s, output: output, emitFinal: true}},
+		key:   append([]byte(nil), prefix...),
+	}
+}
+
+// seek positions the iterator before the first key greater or equal to start.
+func (it *iterator) seek(start []byte) {
+	for _, ch := range start {
+		top := &it.stack[len(it.stack)-1]
+		top.emitFinal = false // keys that are proper prefixes of start are smaller
+
+		arcs := it.f.arcsOf(top.s)
+		idx := sort.Search(len(arcs), func(i int) bool { return arcs[i].label >= ch })
+
+		top.nextArc = idx
+		if idx == len(arcs) || arcs[idx].label != ch {
+			return
+		}
+
+		top.nextArc++
+		it.push(arcs[idx], top.output)
+	}
+}
+
+// push follows the transition.
+func (it *iterator) push(a arc, output uint64) {
+	it.key = append(it.key, a.label)
+	it.stack = append(it.stack, frame{
+		s:         &it.f.states[a.target],
+		output:    output + a.output,
+		emitFinal: true,
+	})
+}
+
+// next moves the iterator to the next key.
+// It returns false if there are no more keys.
+func (it *iterator) next() bool {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+
+		if top.emitFinal {
+			top.emitFinal = false
+
+			if top.s.final {
+				it.value = top.output + top.s.finalOutput
+
+				return true
+			}
+		}
+
+		if arcs := it.f.arcsOf(top.s); top.nextArc < len(arcs) {
+			top.nextArc++
+			it.push(arcs[top.nextArc-1], top.output)
+
+			continue
+		}
+
+		it.stack = it.stack[:len(it.stack)-1]
+		if len(it.stack) > 0 {
+			it.key = it.key[:len(it.key)-1]
+		}
+	}
+
+	return false
+}
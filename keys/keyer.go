@@ -0,0 +1,158 @@
+package keys
+
+import art "github.com/plar/go-adaptive-radix-tree/v2"
+
+// String is a string key of art.Map.
+type String string
+
+// Int is a signed integer key of art.Map.
+type Int int64
+
+// Uint is an unsigned integer key of art.Map.
+type Uint uint64
+
+// Bytes is a byte slice key of art.Map.
+type Bytes []byte
+
+// Tuple2 is a composite key of art.Map ordered by First, then by Second.
+type Tuple2[A art.Keyer[A], B art.Keyer[B]] struct {
+	First  A
+	Second B
+}
+
+// Tuple3 is a composite key of art.Map ordered by First, then by Second, then by Third.
+type Tuple3[A art.Keyer[A], B art.Keyer[B], C art.Keyer[C]] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// assert that the key types implement the art.Keyer and art.PrefixKeyer interfaces.
+var (
+	_ art.Keyer[String]                    = String("")
+	_ art.Keyer[Int]                       = Int(0)
+	_ art.Keyer[Uint]                      = Uint(0)
+	_ art.Keyer[Bytes]                     = Bytes(nil)
+	_ art.Keyer[Tuple2[String, Int]]       = Tuple2[String, Int]{}
+	_ art.Keyer[Tuple3[String, Int, Uint]] = Tuple3[String, Int, Uint]{}
+	_ art.PrefixKeyer                      = String("")
+	_ art.PrefixKeyer                      = Bytes(nil)
+	_ art.PrefixKeyer                      = Tuple2[String, String]{}
+	_ art.PrefixKeyer                      = Tuple3[String, String, String]{}
+)
+
+// AppendKey appends the encoded key to dst, see AppendString.
+func (k String) AppendKey(dst []byte) []byte {
+	return AppendString(dst, string(k))
+}
+
+// DecodeKey decodes the key from the start of b.
+func (String) DecodeKey(b []byte) (String, []byte, error) {
+	s, rest, err := DecodeString(b)
+
+	return String(s), rest, err
+}
+
+// AppendPrefix appends the encoded key without the terminator to dst.
+func (k String) AppendPrefix(dst []byte) []byte {
+	return AppendStringPrefix(dst, string(k))
+}
+
+// AppendKey appends the encoded key to dst, see AppendInt64.
+func (k Int) AppendKey(dst []byte) []byte {
+	return AppendInt64(dst, int64(k))
+}
+
+// DecodeKey decodes the key from the start of b.
+func (Int) DecodeKey(b []byte) (Int, []byte, error) {
+	v, rest, err := DecodeInt64(b)
+
+	return Int(v), rest, err
+}
+
+// AppendKey appends the encoded key to dst, see AppendUint64.
+func (k Uint) AppendKey(dst []byte) []byte {
+	return AppendUint64(dst, uint64(k))
+}
+
+// DecodeKey decodes the key from the start of b.
+func (Uint) DecodeKey(b []byte) (Uint, []byte, error) {
+	v, rest, err := DecodeUint64(b)
+
+	return Uint(v), rest, err
+}
+
+// AppendKey appends the encoded key to dst, see AppendBytes.
+func (k Bytes) AppendKey(dst []byte) []byte {
+	return AppendBytes(dst, k)
+}
+
+// DecodeKey decodes the key from the start of b.
+func (Bytes) DecodeKey(b []byte) (Bytes, []byte, error) {
+	v, rest, err := DecodeBytes(b)
+
+	return Bytes(v), rest, err
+}
+
+// AppendPrefix appends the encoded key without the terminator to dst.
+func (k Bytes) AppendPrefix(dst []byte) []byte {
+	return AppendBytesPrefix(dst, k)
+}
+
+// AppendKey appends the encoded key to dst.
+func (k Tuple2[A, B]) AppendKey(dst []byte) []byte {
+	return k.Second.AppendKey(k.First.AppendKey(dst))
+}
+
+// DecodeKey decodes the key from the start of b.
+func (Tuple2[A, B]) DecodeKey(b []byte) (Tuple2[A, B], []byte, error) {
+	var (
+		k   Tuple2[A, B]
+		err error
+	)
+
+	if k.First, b, err = k.First.DecodeKey(b); err != nil {
+		return k, b, err
+	}
+
+	k.Second, b, err = k.Second.DecodeKey(b)
+
+	return k, b, err
+}
+
+// AppendPrefix appends the encoded First and the prefix encoding of Second to dst.
+// Second is encoded as the whole key if it does not implement PrefixKeyer.
+func (k Tuple2[A, B]) AppendPrefix(dst []byte) []byte {
+	return art.AppendKeyPrefix(k.First.AppendKey(dst), k.Second)
+}
+
+// AppendKey appends the encoded key to dst.
+func (k Tuple3[A, B, C]) AppendKey(dst []byte) []byte {
+	return k.Third.AppendKey(k.Second.AppendKey(k.First.AppendKey(dst)))
+}
+
+// DecodeKey decodes the key from the start of b.
+func (Tuple3[A, B, C]) DecodeKey(b []byte) (Tuple3[A, B, C], []byte, error) {
+	var (
+		k   Tuple3[A, B, C]
+		err error
+	)
+
+	if k.First, b, err = k.First.DecodeKey(b); err != nil {
+		return k, b, err
+	}
+
+	if k.Second, b, err = k.Second.DecodeKey(b); err != nil {
+		return k, b, err
+	}
+
+	k.Third, b, err = k.Third.DecodeKey(b)
+
+	return k, b, err
+}
+
+// AppendPrefix appends the encoded First and Second and the prefix encoding of Third to dst.
+// Third is encoded as the whole key if it does not implement PrefixKeyer.
+func (k Tuple3[A, B, C]) AppendPrefix(dst []byte) []byte {
+	return art.AppendKeyPrefix(k.Second.AppendKey(k.First.AppendKey(dst)), k.Third)
+}
@@ -0,0 +1,89 @@
+package keys
+
+import (
+	"bytes"
+	"testing"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertKeyerRoundTrip asserts that the keys are encoded in ascending order and decode back.
+func assertKeyerRoundTrip[K art.Keyer[K]](t *testing.T, ordered ...K) {
+	t.Helper()
+
+	var prev []byte
+
+	for i, k := range ordered {
+		encoded := k.AppendKey([]byte("p"))[1:]
+		if i > 0 {
+			assert.Equal(t, -1, bytes.Compare(prev, encoded), "%v", k)
+		}
+
+		var zero K
+
+		decoded, rest, err := zero.DecodeKey(append(encoded, "rest"...))
+		require.NoError(t, err)
+		assert.Equal(t, k, decoded)
+		assert.Equal(t, []byte("rest"), rest)
+
+		prev = encoded
+	}
+}
+
+func TestKeyers(t *testing.T) {
+	t.Parallel()
+
+	assertKeyerRoundTrip(t, String(""), String("\x00"), String("a"), String("a\x00"), String("ab"))
+	assertKeyerRoundTrip(t, Bytes{}, Bytes{0}, Bytes{0, 1}, Bytes{1})
+	assertKeyerRoundTrip(t, Int(-10), Int(-9), Int(0), Int(9), Int(10))
+	assertKeyerRoundTrip(t, Uint(0), Uint(9), Uint(10), Uint(1<<63))
+	assertKeyerRoundTrip(t,
+		Tuple2[String, Int]{"a", 10},
+		Tuple2[String, Int]{"a", 11},
+		Tuple2[String, Int]{"aa", -1},
+		Tuple2[String, Int]{"b", -1},
+	)
+	assertKeyerRoundTrip(t,
+		Tuple3[Int, String, Uint]{-1, "z", 0},
+		Tuple3[Int, String, Uint]{0, "", 5},
+		Tuple3[Int, String, Uint]{0, "a", 1},
+		Tuple3[Int, String, Uint]{0, "a", 2},
+	)
+}
+
+func TestKeyersDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Int(0).DecodeKey([]byte{1})
+	require.Error(t, err)
+
+	_, _, err = Uint(0).DecodeKey(nil)
+	require.Error(t, err)
+
+	_, _, err = String("").DecodeKey([]byte("abc"))
+	require.Error(t, err)
+
+	_, _, err = Tuple2[String, Int]{}.DecodeKey([]byte("abc"))
+	require.Error(t, err)
+
+	_, _, err = Tuple2[String, Int]{}.DecodeKey(String("abc").AppendKey(nil))
+	require.Error(t, err)
+
+	_, _, err = Tuple3[Int, Int, Int]{}.DecodeKey(Int(1).AppendKey(nil))
+	require.Error(t, err)
+}
+
+func TestKeyersPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, bytes.HasPrefix(String("abc").AppendKey(nil), String("ab").AppendPrefix(nil)))
+	assert.False(t, bytes.HasPrefix(String("abc").AppendKey(nil), String("ab").AppendKey(nil)))
+	assert.True(t, bytes.HasPrefix(Bytes("abc").AppendKey(nil), Bytes("ab").AppendPrefix(nil)))
+
+	full := Tuple3[Int, Int, String]{1, 2, "abc"}.AppendKey(nil)
+	assert.True(t, bytes.HasPrefix(full, Tuple3[Int, Int, String]{1, 2, "a"}.AppendPrefix(nil)))
+	assert.True(t, bytes.HasPrefix(full, Tuple2[Int, Int]{1, 2}.AppendPrefix(nil)))
+	assert.False(t, bytes.HasPrefix(full, Tuple2[Int, Int]{1, 3}.AppendPrefix(nil)))
+}
@@ -0,0 +1,198 @@
+// Package keys provides order-preserving encodings of Go values into tree keys.
+//
+// The tree orders keys by their bytes. The encoders in this package produce bytes
+// that compare in the same order as the encoded values, so ForEach, prefix and range
+// scans over the encoded keys return the values in their natural order:
+//
+//	tree.Insert(keys.AppendInt64(nil, -10), "minus ten")
+//	tree.Insert(keys.AppendInt64(nil, 9), "nine")
+//	tree.Insert(keys.AppendInt64(nil, 10), "ten")
+//	// ForEach visits -10, 9, 10.
+//
+// Integers, floats and times are encoded with a fixed width, strings and byte slices
+// are escaped and terminated, so the encodings can be concatenated into composite keys,
+// see AppendTuple. A key built from several values sorts by the first value,
+// then by the second one and so on.
+//
+// Every Append function has a matching Decode function that decodes the value
+// from the start of the buffer and returns the remaining bytes.
+//
+// The key types String, Bytes, Int, Uint, Tuple2 and Tuple3 implement art.Keyer,
+// so they can be used as the keys of art.Map.
+package keys
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidEncoding is returned when the bytes are not a valid encoding of the value.
+var ErrInvalidEncoding = errors.New("keys: invalid encoding")
+
+const (
+	signBit = 1 << 63
+
+	// escape starts the two byte sequence of an escaped zero byte or the terminator.
+	escape = 0x00
+
+	// escapedZero follows escape for the zero byte of the string.
+	escapedZero = 0xff
+
+	// terminator follows escape at the end of the string.
+	// It is less than escapedZero and any other byte, so shorter strings sort first.
+	terminator = 0x01
+)
+
+// AppendUint64 appends the 8 byte big-endian encoding of v to dst.
+func AppendUint64(dst []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+
+	return append(dst, buf[:]...)
+}
+
+// DecodeUint64 decodes the value encoded by AppendUint64 from the start of b.
+func DecodeUint64(b []byte) (uint64, []byte, error) {
+	if len(b) < 8 {
+		return 0, b, ErrInvalidEncoding
+	}
+
+	return binary.BigEndian.Uint64(b), b[8:], nil
+}
+
+// AppendInt64 appends the encoding of v to dst.
+// The sign bit is flipped, so negative numbers sort before positive ones.
+func AppendInt64(dst []byte, v int64) []byte {
+	return AppendUint64(dst, uint64(v)^signBit) //#nosec:G115
+}
+
+// DecodeInt64 decodes the value encoded by AppendInt64 from the start of b.
+func DecodeInt64(b []byte) (int64, []byte, error) {
+	u, rest, err := DecodeUint64(b)
+
+	return int64(u ^ signBit), rest, err //#nosec:G115
+}
+
+// AppendFloat64 appends the encoding of v to dst.
+// Positive numbers have the sign bit set, negative numbers have all bits flipped,
+// so the numbers sort by value: -Inf, negative numbers, -0, +0, positive numbers, +Inf.
+// NaNs sort before -Inf or after +Inf depending on their sign bit.
+func AppendFloat64(dst []byte, v float64) []byte {
+	bits := math.Float64bits(v)
+	if bits&signBit != 0 {
+		bits = ^bits
+	} else {
+		bits |= signBit
+	}
+
+	return AppendUint64(dst, bits)
+}
+
+// DecodeFloat64 decodes the value encoded by AppendFloat64 from the start of b.
+func DecodeFloat64(b []byte) (float64, []byte, error) {
+	bits, rest, err := DecodeUint64(b)
+	if bits&signBit != 0 {
+		bits &^= signBit
+	} else {
+		bits = ^bits
+	}
+
+	return math.Float64frombits(bits), rest, err
+}
+
+// AppendTime appends the encoding of t to dst: the Unix seconds as AppendInt64
+// followed by the 4 byte big-endian nanoseconds. The location is not encoded.
+func AppendTime(dst []byte, t time.Time) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(t.Nanosecond())) //#nosec:G115
+
+	return append(AppendInt64(dst, t.Unix()), buf[:]...)
+}
+
+// DecodeTime decodes the value encoded by AppendTime from the start of b.
+// The time is returned in UTC.
+func DecodeTime(b []byte) (time.Time, []byte, error) {
+	sec, rest, err := DecodeInt64(b)
+	if err != nil || len(rest) < 4 {
+		return time.Time{}, b, ErrInvalidEncoding
+	}
+
+	nsec := binary.BigEndian.Uint32(rest)
+
+	return time.Unix(sec, int64(nsec)).UTC(), rest[4:], nil
+}
+
+// AppendString appends the encoding of s to dst.
+// Zero bytes are escaped as 0x00 0xff and the string is terminated by 0x00 0x01.
+func AppendString(dst []byte, s string) []byte {
+	return append(appendEscaped(dst, s), escape, terminator)
+}
+
+// AppendStringPrefix appends the encoding of s without the terminator to dst.
+// The result is a prefix of the encoding of every string starting with s,
+// so it can be used for prefix scans with ForEachPrefix.
+func AppendStringPrefix(dst []byte, s string) []byte {
+	return appendEscaped(dst, s)
+}
+
+// DecodeString decodes the value encoded by AppendString from the start of b.
+func DecodeString(b []byte) (string, []byte, error) {
+	s, rest, err := DecodeBytes(b)
+
+	return string(s), rest, err
+}
+
+// AppendBytes appends the encoding of s to dst, the same as AppendString.
+func AppendBytes(dst []byte, s []byte) []byte {
+	return append(appendEscaped(dst, s), escape, terminator)
+}
+
+// AppendBytesPrefix appends the encoding of s without the terminator to dst, see AppendStringPrefix.
+func AppendBytesPrefix(dst []byte, s []byte) []byte {
+	return appendEscaped(dst, s)
+}
+
+// DecodeBytes decodes the value encoded by AppendBytes from the start of b.
+// The returned slice does not share memory with b.
+func DecodeBytes(b []byte) ([]byte, []byte, error) {
+	out := make([]byte, 0, len(b))
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != escape {
+			out = append(out, b[i])
+
+			continue
+		}
+
+		if i+1 == len(b) {
+			break
+		}
+
+		switch b[i+1] {
+		case escapedZero:
+			out = append(out, 0)
+			i++
+		case terminator:
+			return out, b[i+2:], nil
+		default:
+			return nil, b, ErrInvalidEncoding
+		}
+	}
+
+	return nil, b, ErrInvalidEncoding
+}
+
+// appendEscaped appends s to dst with the zero bytes escaped.
+func appendEscaped[T string | []byte](dst []byte, s T) []byte {
+	for i := 0; i < len(s); i++ {
+		if s[i] == escape {
+			dst = append(dst, escape, escapedZero)
+		} else {
+			dst = append(dst, s[i])
+		}
+	}
+
+	return dst
+}
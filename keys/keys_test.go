@@ -0,0 +1,186 @@
+package keys
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertOrdered asserts that the encodings are strictly ascending.
+func assertOrdered(t *testing.T, encoded [][]byte) {
+	t.Helper()
+
+	for i := 1; i < len(encoded); i++ {
+		assert.Equal(t, -1, bytes.Compare(encoded[i-1], encoded[i]), "%d: %x >= %x", i, encoded[i-1], encoded[i])
+	}
+}
+
+func TestInt64(t *testing.T) {
+	t.Parallel()
+
+	values := []int64{math.MinInt64, -1 << 32, -10, -9, -1, 0, 1, 9, 10, 1 << 32, math.MaxInt64}
+
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b := AppendInt64(nil, v)
+		encoded = append(encoded, b)
+
+		got, rest, err := DecodeInt64(b)
+		require.NoError(t, err)
+		assert.Equal(t, v, got)
+		assert.Empty(t, rest)
+	}
+
+	assertOrdered(t, encoded)
+}
+
+func TestUint64(t *testing.T) {
+	t.Parallel()
+
+	values := []uint64{0, 1, 9, 10, 255, 256, 1 << 32, math.MaxUint64}
+
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b := AppendUint64([]byte("p"), v)
+		encoded = append(encoded, b)
+
+		got, rest, err := DecodeUint64(b[1:])
+		require.NoError(t, err)
+		assert.Equal(t, v, got)
+		assert.Empty(t, rest)
+	}
+
+	assertOrdered(t, encoded)
+}
+
+func TestFloat64(t *testing.T) {
+	t.Parallel()
+
+	values := []float64{
+		math.Inf(-1), -math.MaxFloat64, -1e10, -1.5, -1, -math.SmallestNonzeroFloat64, math.Copysign(0, -1),
+		0, math.SmallestNonzeroFloat64, 1, 1.5, 1e10, math.MaxFloat64, math.Inf(1),
+	}
+
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b := AppendFloat64(nil, v)
+		encoded = append(encoded, b)
+
+		got, rest, err := DecodeFloat64(b)
+		require.NoError(t, err)
+		assert.Equal(t, math.Float64bits(v), math.Float64bits(got))
+		assert.Empty(t, rest)
+	}
+
+	assertOrdered(t, encoded)
+
+	got, _, err := DecodeFloat64(AppendFloat64(nil, math.NaN()))
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(got))
+}
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC)
+	values := []time.Time{
+		time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(-1, 999999999),
+		time.Unix(0, 0),
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(time.Second),
+	}
+
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b := AppendTime(nil, v)
+		encoded = append(encoded, b)
+
+		got, rest, err := DecodeTime(b)
+		require.NoError(t, err)
+		assert.True(t, v.Equal(got), "%v != %v", v, got)
+		assert.Equal(t, time.UTC, got.Location())
+		assert.Empty(t, rest)
+	}
+
+	assertOrdered(t, encoded)
+
+	local := base.In(time.FixedZone("UTC+3", 3*60*60))
+	assert.Equal(t, AppendTime(nil, base), AppendTime(nil, local))
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"", "\x00", "\x00\x00", "\x00\x01", "\x01", "a", "a\x00", "a\x00b", "a\x01", "aa", "ab", "b", "\xff"}
+
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		b := AppendString(nil, v)
+		encoded = append(encoded, b)
+
+		got, rest, err := DecodeString(append(b, "rest"...))
+		require.NoError(t, err)
+		assert.Equal(t, v, got)
+		assert.Equal(t, []byte("rest"), rest)
+
+		gotBytes, _, err := DecodeBytes(AppendBytes(nil, []byte(v)))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(v), gotBytes)
+
+		assert.True(t, bytes.HasPrefix(b, AppendStringPrefix(nil, v)))
+		assert.Equal(t, AppendStringPrefix(nil, v), AppendBytesPrefix(nil, []byte(v)))
+	}
+
+	assertOrdered(t, encoded)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := DecodeUint64([]byte{1, 2, 3})
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	_, _, err = DecodeInt64(nil)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	_, _, err = DecodeFloat64([]byte{1})
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	_, _, err = DecodeTime(AppendInt64(nil, 1))
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	for _, b := range []string{"", "abc", "abc\x00", "abc\x00\x02"} {
+		_, rest, err := DecodeString([]byte(b))
+		require.ErrorIs(t, err, ErrInvalidEncoding, "%q", b)
+		assert.Equal(t, []byte(b), rest)
+	}
+}
+
+func TestTreeOrder(t *testing.T) {
+	t.Parallel()
+
+	tree := art.New()
+	for _, v := range []int64{10, -1, 9, 100, -100, 0} {
+		tree.Insert(AppendInt64(nil, v), v)
+	}
+
+	var got []int64
+
+	tree.ForEach(func(node art.Node) bool {
+		v, _, err := DecodeInt64(node.Key())
+		require.NoError(t, err)
+
+		got = append(got, v)
+
+		return true
+	})
+
+	assert.Equal(t, []int64{-100, -1, 0, 9, 10, 100}, got)
+}
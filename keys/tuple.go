@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupportedType is returned for the tuple values of unsupported types.
+var ErrUnsupportedType = errors.New("keys: unsupported type")
+
+// AppendTuple appends the encodings of the values to dst, so the composite key
+// sorts by the first value, then by the second one and so on.
+//
+// The supported types are bool, signed and unsigned integers, float32, float64,
+// string, []byte and time.Time. Signed integers are encoded as int64,
+// unsigned integers as uint64 and floats as float64. The types are not encoded,
+// so all keys of the tree should use the same types at the same positions.
+func AppendTuple(dst []byte, values ...interface{}) ([]byte, error) {
+	for _, value := range values {
+		switch v := value.(type) {
+		case bool:
+			dst = append(dst, boolByte(v))
+		case int:
+			dst = AppendInt64(dst, int64(v))
+		case int8:
+			dst = AppendInt64(dst, int64(v))
+		case int16:
+			dst = AppendInt64(dst, int64(v))
+		case int32:
+			dst = AppendInt64(dst, int64(v))
+		case int64:
+			dst = AppendInt64(dst, v)
+		case uint:
+			dst = AppendUint64(dst, uint64(v))
+		case uint8:
+			dst = AppendUint64(dst, uint64(v))
+		case uint16:
+			dst = AppendUint64(dst, uint64(v))
+		case uint32:
+			dst = AppendUint64(dst, uint64(v))
+		case uint64:
+			dst = AppendUint64(dst, v)
+		case float32:
+			dst = AppendFloat64(dst, float64(v))
+		case float64:
+			dst = AppendFloat64(dst, v)
+		case string:
+			dst = AppendString(dst, v)
+		case []byte:
+			dst = AppendBytes(dst, v)
+		case time.Time:
+			dst = AppendTime(dst, v)
+		default:
+			return dst, fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+		}
+	}
+
+	return dst, nil
+}
+
+// DecodeTuple decodes the values encoded by AppendTuple from the start of b
+// into the pointers, e.g. *int, *string or *time.Time, and returns the remaining bytes.
+//
+//nolint:cyclop,funlen
+func DecodeTuple(b []byte, ptrs ...interface{}) ([]byte, error) {
+	var err error
+
+	for _, ptr := range ptrs {
+		switch p := ptr.(type) {
+		case *bool:
+			if len(b) == 0 || b[0] > 1 {
+				return b, ErrInvalidEncoding
+			}
+
+			*p, b = b[0] == 1, b[1:]
+		case *int:
+			var v int64
+			v, b, err = DecodeInt64(b)
+			*p = int(v)
+		case *int8:
+			var v int64
+			v, b, err = DecodeInt64(b)
+			*p = int8(v) //#nosec:G115
+		case *int16:
+			var v int64
+			v, b, err = DecodeInt64(b)
+			*p = int16(v) //#nosec:G115
+		case *int32:
+			var v int64
+			v, b, err = DecodeInt64(b)
+			*p = int32(v) //#nosec:G115
+		case *int64:
+			*p, b, err = DecodeInt64(b)
+		case *uint:
+			var v uint64
+			v, b, err = DecodeUint64(b)
+			*p = uint(v)
+		case *uint8:
+			var v uint64
+			v, b, err = DecodeUint64(b)
+			*p = uint8(v) //#nosec:G115
+		case *uint16:
+			var v uint64
+			v, b, err = DecodeUint64(b)
+			*p = uint16(v) //#nosec:G115
+		case *uint32:
+			var v uint64
+			v, b, err = DecodeUint64(b)
+			*p = uint32(v) //#nosec:G115
+		case *uint64:
+			*p, b, err = DecodeUint64(b)
+		case *float32:
+			var v float64
+			v, b, err = DecodeFloat64(b)
+			*p = float32(v)
+		case *float64:
+			*p, b, err = DecodeFloat64(b)
+		case *string:
+			*p, b, err = DecodeString(b)
+		case *[]byte:
+			*p, b, err = DecodeBytes(b)
+		case *time.Time:
+			*p, b, err = DecodeTime(b)
+		default:
+			return b, fmt.Errorf("%w: %T", ErrUnsupportedType, ptr)
+		}
+
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// boolByte returns the encoding of the bool value.
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,136 @@
+package keys
+
+import (
+	"testing"
+	"time"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTupleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	b, err := AppendTuple(nil,
+		true, -1, int8(-8), int16(-16), int32(-32), int64(-64),
+		uint(1), uint8(8), uint16(16), uint32(32), uint64(64),
+		float32(1.5), -2.5, "a\x00b", []byte{0, 1}, now)
+	require.NoError(t, err)
+
+	var (
+		vBool    bool
+		vInt     int
+		vInt8    int8
+		vInt16   int16
+		vInt32   int32
+		vInt64   int64
+		vUint    uint
+		vUint8   uint8
+		vUint16  uint16
+		vUint32  uint32
+		vUint64  uint64
+		vFloat32 float32
+		vFloat64 float64
+		vString  string
+		vBytes   []byte
+		vTime    time.Time
+	)
+
+	rest, err := DecodeTuple(b,
+		&vBool, &vInt, &vInt8, &vInt16, &vInt32, &vInt64,
+		&vUint, &vUint8, &vUint16, &vUint32, &vUint64,
+		&vFloat32, &vFloat64, &vString, &vBytes, &vTime)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+
+	assert.True(t, vBool)
+	assert.Equal(t, -1, vInt)
+	assert.Equal(t, int8(-8), vInt8)
+	assert.Equal(t, int16(-16), vInt16)
+	assert.Equal(t, int32(-32), vInt32)
+	assert.Equal(t, int64(-64), vInt64)
+	assert.Equal(t, uint(1), vUint)
+	assert.Equal(t, uint8(8), vUint8)
+	assert.Equal(t, uint16(16), vUint16)
+	assert.Equal(t, uint32(32), vUint32)
+	assert.Equal(t, uint64(64), vUint64)
+	assert.Equal(t, float32(1.5), vFloat32)
+	assert.Equal(t, -2.5, vFloat64)
+	assert.Equal(t, "a\x00b", vString)
+	assert.Equal(t, []byte{0, 1}, vBytes)
+	assert.Equal(t, now, vTime)
+}
+
+func TestTupleErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := AppendTuple(nil, 1, struct{}{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	b, err := AppendTuple(nil, 1)
+	require.NoError(t, err)
+
+	var s struct{}
+
+	_, err = DecodeTuple(b, &s)
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	var (
+		i    int
+		str  string
+		flag bool
+	)
+
+	_, err = DecodeTuple(b, &i, &str)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	_, err = DecodeTuple([]byte{2}, &flag)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+}
+
+func TestTupleTreeOrderAndPrefix(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		user string
+		seq  int
+	}
+
+	rows := []row{{"bob", 10}, {"al", 9}, {"bob", -1}, {"alice", 2}, {"bob", 9}, {"al", 10}}
+
+	tree := art.New()
+
+	for _, r := range rows {
+		key, err := AppendTuple(nil, r.user, r.seq)
+		require.NoError(t, err)
+		tree.Insert(key, r)
+	}
+
+	var got []row
+
+	collect := func(node art.Node) bool {
+		var r row
+
+		_, err := DecodeTuple(node.Key(), &r.user, &r.seq)
+		require.NoError(t, err)
+
+		got = append(got, r)
+
+		return true
+	}
+
+	scan := func(prefix []byte) []row {
+		got = nil
+		tree.ForEachPrefix(prefix, collect)
+
+		return got
+	}
+
+	tree.ForEach(collect)
+	assert.Equal(t, []row{{"al", 9}, {"al", 10}, {"alice", 2}, {"bob", -1}, {"bob", 9}, {"bob", 10}}, got)
+	assert.Equal(t, []row{{"al", 9}, {"al", 10}}, scan(AppendString(nil, "al")))
+	assert.Equal(t, []row{{"al", 9}, {"al", 10}, {"alice", 2}}, scan(AppendStringPrefix(nil, "al")))
+}
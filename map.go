@@ -0,0 +1,161 @@
+package art
+
+import "fmt"
+
+// Map is an ordered map backed by the adaptive radix tree.
+// The keys are encoded with an order-preserving encoding, see Keyer,
+// so the iteration returns them in their natural order, e.g. -1 < 9 < 10 for keys.Int.
+//
+//	m := art.NewMap[keys.Int, string]()
+//	m.Insert(10, "ten")
+//	m.Insert(9, "nine")
+//	m.ForEach(func(key keys.Int, value string) bool { ... }) // 9, 10
+type Map[K Keyer[K], V any] struct {
+	tree *tree
+}
+
+// NewMap creates a new empty map.
+func NewMap[K Keyer[K], V any](opts ...TreeOption) *Map[K, V] {
+	tr, _ := NewWithOptions(opts...).(*tree)
+
+	return &Map[K, V]{tree: tr}
+}
+
+// Insert inserts the key and value into the map.
+// If the key already exists, it updates the value and
+// returns the old value with second return value set to true.
+func (m *Map[K, V]) Insert(key K, value V) (V, bool) {
+	old, updated := m.tree.Insert(key.AppendKey(nil), value)
+
+	return m.value(old), updated
+}
+
+// Delete removes the key from the map.
+// If the key exists, it returns the value and true.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	value, deleted := m.tree.Delete(key.AppendKey(nil))
+
+	return m.value(value), deleted
+}
+
+// Search returns the value associated with the key.
+// If the key does not exist, it returns the zero value and false.
+func (m *Map[K, V]) Search(key K) (V, bool) {
+	value, found := m.tree.Search(key.AppendKey(nil))
+
+	return m.value(value), found
+}
+
+// Minimum returns the smallest key and its value.
+// If the map is empty, it returns false.
+func (m *Map[K, V]) Minimum() (K, V, bool) {
+	if m.tree.root == nil {
+		var key K
+
+		return key, m.value(nil), false
+	}
+
+	key, value := m.entry(m.tree.root.minimum())
+
+	return key, value, true
+}
+
+// Maximum returns the largest key and its value.
+// If the map is empty, it returns false.
+func (m *Map[K, V]) Maximum() (K, V, bool) {
+	if m.tree.root == nil {
+		var key K
+
+		return key, m.value(nil), false
+	}
+
+	key, value := m.entry(m.tree.root.maximum())
+
+	return key, value, true
+}
+
+// Size returns the number of keys in the map.
+func (m *Map[K, V]) Size() int {
+	return m.tree.Size()
+}
+
+// ForEach calls the callback for every key and value in ascending order.
+// Pass the TraverseReverse option to iterate in descending order.
+// The iteration stops if the callback returns false.
+func (m *Map[K, V]) ForEach(callback func(key K, value V) bool, opts ...int) {
+	m.tree.ForEach(m.callback(callback), mergeOptions(opts...)&TraverseReverse)
+}
+
+// ForEachPrefix calls the callback for every key starting with the prefix in ascending order.
+// The prefix is encoded with PrefixKeyer if K implements it, so for example
+// keys.String("ab") matches "ab" and "abc", otherwise only the prefix key itself matches.
+// Pass the TraverseReverse option to iterate in descending order.
+// The iteration stops if the callback returns false.
+func (m *Map[K, V]) ForEachPrefix(prefix K, callback func(key K, value V) bool, opts ...int) {
+	m.tree.ForEachPrefix(AppendKeyPrefix(nil, prefix), m.callback(callback), mergeOptions(opts...)&TraverseReverse)
+}
+
+// Range calls the callback for every key in the range [start, end) in ascending order.
+// Pass the TraverseReverse option to iterate in descending order.
+// The iteration stops if the callback returns false.
+func (m *Map[K, V]) Range(start, end K, callback func(key K, value V) bool, opts ...int) {
+	kr := keyRange{start: start.AppendKey(nil), end: end.AppendKey(nil)}
+	reverse := mergeOptions(opts...)&TraverseReverse == TraverseReverse
+
+	m.tree.forEachRange(m.tree.root, kr, true, true, m.callback(callback), reverse)
+}
+
+// RangeFrom calls the callback for every key greater than or equal to start in ascending order.
+// Pass the TraverseReverse option to iterate in descending order.
+// The iteration stops if the callback returns false.
+func (m *Map[K, V]) RangeFrom(start K, callback func(key K, value V) bool, opts ...int) {
+	kr := keyRange{start: start.AppendKey(nil)}
+	reverse := mergeOptions(opts...)&TraverseReverse == TraverseReverse
+
+	m.tree.forEachRange(m.tree.root, kr, true, false, m.callback(callback), reverse)
+}
+
+// Tree returns the underlying tree, e.g. for Stats, Verify or Freeze.
+// The keys of the tree are the encoded map keys. The tree must not be
+// modified directly, unless the inserted keys are encoded by K,
+// the map panics when it visits a key that K cannot decode.
+func (m *Map[K, V]) Tree() Tree {
+	return m.tree
+}
+
+// callback converts the typed callback to the tree callback.
+func (m *Map[K, V]) callback(callback func(key K, value V) bool) Callback {
+	return func(node Node) bool {
+		return callback(m.key(node.Key()), m.value(node.Value()))
+	}
+}
+
+// entry returns the decoded key and value of the leaf.
+func (m *Map[K, V]) entry(l *leaf) (K, V) {
+	return m.key(l.key), m.value(l.value)
+}
+
+// key decodes the tree key to K.
+// The keys are encoded by the map, so a key that does not decode
+// has been inserted into the tree directly, it is a programming error.
+func (m *Map[K, V]) key(encoded Key) K {
+	var key K
+
+	key, rest, err := key.DecodeKey(encoded)
+	if err == nil && len(rest) > 0 {
+		err = fmt.Errorf("%d trailing bytes", len(rest))
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("art: cannot decode the map key %q: %v", encoded, err))
+	}
+
+	return key
+}
+
+// value converts the tree value to V, nil is converted to the zero value.
+func (m *Map[K, V]) value(v Value) V {
+	typed, _ := v.(V)
+
+	return typed
+}
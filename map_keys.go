@@ -0,0 +1,32 @@
+package art
+
+// Keyer is a key type of Map.
+// The key is encoded to the tree key with an order-preserving encoding,
+// so the map is ordered by the natural order of the keys.
+// The keys package provides the ready-made key types, e.g. keys.String and keys.Tuple2.
+// The encoding must be self-delimiting to be used in the tuple keys.
+type Keyer[K any] interface {
+	// AppendKey appends the encoded key to dst.
+	AppendKey(dst []byte) []byte
+
+	// DecodeKey decodes the key from the start of b and returns the remaining bytes.
+	// The receiver is not used, DecodeKey is called on the zero value of K.
+	DecodeKey(b []byte) (K, []byte, error)
+}
+
+// PrefixKeyer is implemented by the key types that support prefix scans, see Map.ForEachPrefix.
+type PrefixKeyer interface {
+	// AppendPrefix appends the encoding of the key that is a prefix
+	// of the encoded keys starting with the key, e.g. a string without the terminator.
+	AppendPrefix(dst []byte) []byte
+}
+
+// AppendKeyPrefix appends the prefix encoding of the key if it implements PrefixKeyer,
+// otherwise the encoded key. It can be used to implement PrefixKeyer for composite keys.
+func AppendKeyPrefix[K Keyer[K]](dst []byte, key K) []byte {
+	if pk, ok := interface{}(key).(PrefixKeyer); ok {
+		return pk.AppendPrefix(dst)
+	}
+
+	return key.AppendKey(dst)
+}
@@ -0,0 +1,282 @@
+package art_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	art "github.com/plar/go-adaptive-radix-tree/v2"
+	"github.com/plar/go-adaptive-radix-tree/v2/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapEntry is a key and value visited by the Map iteration.
+type mapEntry[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// collectMap returns a callback that appends the visited entries to the slice.
+func collectMap[K any, V any](entries *[]mapEntry[K, V]) func(K, V) bool {
+	return func(key K, value V) bool {
+		*entries = append(*entries, mapEntry[K, V]{key, value})
+
+		return true
+	}
+}
+
+func TestMapIntKeys(t *testing.T) {
+	t.Parallel()
+
+	m := art.NewMap[keys.Int, string]()
+	for _, v := range []int{10, -1, 9, 100, -100, 0} {
+		_, updated := m.Insert(keys.Int(v), "v")
+		assert.False(t, updated)
+	}
+
+	old, updated := m.Insert(keys.Int(9), "nine")
+	assert.True(t, updated)
+	assert.Equal(t, "v", old)
+	assert.Equal(t, 6, m.Size())
+
+	var entries []mapEntry[keys.Int, string]
+
+	m.ForEach(collectMap(&entries))
+	assert.Equal(t, []mapEntry[keys.Int, string]{
+		{-100, "v"}, {-1, "v"}, {0, "v"}, {9, "nine"}, {10, "v"}, {100, "v"},
+	}, entries)
+
+	entries = nil
+
+	m.ForEach(collectMap(&entries), art.TraverseReverse|art.TraverseNode)
+	require.Len(t, entries, 6)
+	assert.Equal(t, keys.Int(100), entries[0].Key)
+
+	key, value, found := m.Minimum()
+	assert.True(t, found)
+	assert.Equal(t, keys.Int(-100), key)
+	assert.Equal(t, "v", value)
+
+	key, value, found = m.Maximum()
+	assert.True(t, found)
+	assert.Equal(t, keys.Int(100), key)
+	assert.Equal(t, "v", value)
+
+	value, found = m.Search(9)
+	assert.True(t, found)
+	assert.Equal(t, "nine", value)
+
+	value, found = m.Search(8)
+	assert.False(t, found)
+	assert.Empty(t, value)
+
+	value, deleted := m.Delete(9)
+	assert.True(t, deleted)
+	assert.Equal(t, "nine", value)
+
+	_, deleted = m.Delete(9)
+	assert.False(t, deleted)
+	assert.Equal(t, 5, m.Size())
+	require.NoError(t, art.Verify(m.Tree()))
+}
+
+func TestMapEmpty(t *testing.T) {
+	t.Parallel()
+
+	m := art.NewMap[keys.String, int]()
+
+	key, value, found := m.Minimum()
+	assert.False(t, found)
+	assert.Empty(t, key)
+	assert.Zero(t, value)
+
+	_, _, found = m.Maximum()
+	assert.False(t, found)
+
+	m.ForEach(func(keys.String, int) bool {
+		assert.Fail(t, "unexpected entry")
+
+		return true
+	})
+}
+
+func TestMapPrefixAndRange(t *testing.T) {
+	t.Parallel()
+
+	m := art.NewMap[keys.String, int]()
+	for i, k := range []string{"", "a", "ab", "abc", "abd", "b", "ba", "c"} {
+		m.Insert(keys.String(k), i)
+	}
+
+	keysOf := func(entries []mapEntry[keys.String, int]) []keys.String {
+		var got []keys.String
+		for _, e := range entries {
+			got = append(got, e.Key)
+		}
+
+		return got
+	}
+
+	var entries []mapEntry[keys.String, int]
+
+	m.ForEachPrefix("ab", collectMap(&entries))
+	assert.Equal(t, []keys.String{"ab", "abc", "abd"}, keysOf(entries))
+
+	entries = nil
+
+	m.ForEachPrefix("ab", collectMap(&entries), art.TraverseReverse)
+	assert.Equal(t, []keys.String{"abd", "abc", "ab"}, keysOf(entries))
+
+	entries = nil
+
+	m.ForEachPrefix("", collectMap(&entries))
+	assert.Len(t, entries, 8)
+
+	tests := []struct {
+		start, end keys.String
+		want       []keys.String
+	}{
+		{"a", "b", []keys.String{"a", "ab", "abc", "abd"}},
+		{"", "a", []keys.String{""}},
+		{"abc", "ba", []keys.String{"abc", "abd", "b"}},
+		{"ab", "abc", []keys.String{"ab"}},
+		{"b", "b", nil},
+		{"c", "b", nil},
+		{"bb", "z", []keys.String{"c"}},
+	}
+
+	for _, tt := range tests {
+		entries = nil
+
+		m.Range(tt.start, tt.end, collectMap(&entries))
+		assert.Equal(t, tt.want, keysOf(entries), "[%q, %q)", tt.start, tt.end)
+	}
+
+	entries = nil
+
+	m.Range("a", "b", collectMap(&entries), art.TraverseReverse)
+	assert.Equal(t, []keys.String{"abd", "abc", "ab", "a"}, keysOf(entries))
+
+	entries = nil
+
+	m.Range("", "z", func(key keys.String, value int) bool {
+		entries = append(entries, mapEntry[keys.String, int]{key, value})
+
+		return len(entries) < 3
+	})
+	assert.Equal(t, []keys.String{"", "a", "ab"}, keysOf(entries))
+
+	entries = nil
+
+	m.RangeFrom("abc", collectMap(&entries))
+	assert.Equal(t, []keys.String{"abc", "abd", "b", "ba", "c"}, keysOf(entries))
+
+	entries = nil
+
+	m.RangeFrom("bb", collectMap(&entries), art.TraverseReverse)
+	assert.Equal(t, []keys.String{"c"}, keysOf(entries))
+
+	entries = nil
+
+	m.RangeFrom("d", collectMap(&entries))
+	assert.Empty(t, entries)
+}
+
+func TestMapRangeWords(t *testing.T) {
+	t.Parallel()
+
+	m := art.NewMap[keys.Bytes, int]()
+	data, err := os.ReadFile("test/assets/words.txt")
+	require.NoError(t, err)
+
+	words := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+
+	for i, w := range words {
+		m.Insert(w, i)
+	}
+
+	count := 0
+
+	m.Range(keys.Bytes("antisa"), keys.Bytes("antisb"), func(key keys.Bytes, _ int) bool {
+		assert.Equal(t, "antisa", string(key[:6]))
+		count++
+
+		return true
+	})
+
+	prefixCount := 0
+
+	m.ForEachPrefix(keys.Bytes("antisa"), func(keys.Bytes, int) bool {
+		prefixCount++
+
+		return true
+	})
+
+	assert.Positive(t, count)
+	assert.Equal(t, prefixCount, count)
+}
+
+func TestMapTupleKeys(t *testing.T) {
+	t.Parallel()
+
+	type key = keys.Tuple2[keys.String, keys.Int]
+
+	m := art.NewMap[key, string]()
+	for _, k := range []key{
+		{First: "bob", Second: 10}, {First: "al", Second: 9}, {First: "bob", Second: -1},
+		{First: "alice", Second: 2}, {First: "bob", Second: 9}, {First: "al", Second: 10},
+	} {
+		m.Insert(k, string(k.First))
+	}
+
+	var entries []mapEntry[key, string]
+
+	m.ForEach(collectMap(&entries))
+	assert.Equal(t, []mapEntry[key, string]{
+		{key{First: "al", Second: 9}, "al"}, {key{First: "al", Second: 10}, "al"}, {key{First: "alice", Second: 2}, "alice"},
+		{key{First: "bob", Second: -1}, "bob"}, {key{First: "bob", Second: 9}, "bob"}, {key{First: "bob", Second: 10}, "bob"},
+	}, entries)
+
+	entries = nil
+
+	// keys.Int does not implement PrefixKeyer, so the whole tuple is the prefix.
+	m.ForEachPrefix(key{First: "bob", Second: 9}, collectMap(&entries))
+	assert.Equal(t, []mapEntry[key, string]{{key{First: "bob", Second: 9}, "bob"}}, entries)
+
+	entries = nil
+
+	m.Range(key{First: "al", Second: 10}, key{First: "bob", Second: 0}, collectMap(&entries))
+	assert.Equal(t, []mapEntry[key, string]{
+		{key{First: "al", Second: 10}, "al"}, {key{First: "alice", Second: 2}, "alice"}, {key{First: "bob", Second: -1}, "bob"},
+	}, entries)
+}
+
+func TestMapHooks(t *testing.T) {
+	t.Parallel()
+
+	splits := 0
+	m := art.NewMap[keys.Uint, int](art.WithHooks(&art.Hooks{OnSplitLeaf: func(int) { splits++ }}))
+	m.Insert(1, 1)
+	m.Insert(2, 2)
+
+	assert.Equal(t, 1, splits)
+}
+
+func TestMapInvalidKeyPanics(t *testing.T) {
+	t.Parallel()
+
+	m := art.NewMap[keys.Int, int]()
+	m.Insert(1, 1)
+	m.Tree().Insert(art.Key("invalid"), 2)
+
+	assert.PanicsWithValue(t, `art: cannot decode the map key "invalid": keys: invalid encoding`, func() {
+		m.ForEach(func(keys.Int, int) bool { return true })
+	})
+	assert.Panics(t, func() { m.Minimum() })
+
+	m.Tree().Delete(art.Key("invalid"))
+	m.Tree().Insert(append(keys.Int(2).AppendKey(nil), 0), 2)
+
+	assert.Panics(t, func() { m.Maximum() })
+}
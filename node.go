@@ -6,9 +6,11 @@ type prefix [maxPrefixLen]byte
 
 // node is the base struct for all node types.
 // it contains the common fields for all nodeX types.
+// prefixLen is wider than childrenLen, because the keys can share prefixes longer than 64KB,
+// and it goes first to avoid padding.
 type node struct {
+	prefixLen   uint32 // length of the prefix
 	prefix      prefix // prefix of the node
-	prefixLen   uint16 // length of the prefix
 	childrenLen uint16 // number of children in the node4, node16, node48, node256
 }
 
@@ -72,7 +72,7 @@ func (n *node256) shrink() *nodeRef {
 	n48 := an48.node48()
 
 	copyNode(&n48.node, &n.node)
-	n48.children[node48Min] = n.children[node256Max] // copy zero byte child
+	n48.children[node48Max] = n.children[node256Max] // copy zero byte child
 
 	for numChildren, i := 0, 0; i < node256Max; i++ {
 		if n.children[i] == nil {
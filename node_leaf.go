@@ -14,8 +14,9 @@ func (l *leaf) match(key Key) bool {
 }
 
 // prefixMatch returns true if the leaf node's key has the given key as a prefix.
+// The nil and empty keys are a prefix of any key.
 func (l *leaf) prefixMatch(key Key) bool {
-	if key == nil || len(l.key) < len(key) {
+	if len(l.key) < len(key) {
 		return false
 	}
 
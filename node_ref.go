@@ -105,8 +105,9 @@ var _ noder = (*node16)(nil)
 var _ noder = (*node48)(nil)
 var _ noder = (*node256)(nil)
 
-// assert that nodeRef implements public Node interface.
+// assert that nodeRef implements public Node and InnerNode interfaces.
 var _ Node = (*nodeRef)(nil)
+var _ InnerNode = (*nodeRef)(nil)
 
 // Kind returns the node kind.
 func (nr *nodeRef) Kind() Kind {
@@ -133,6 +134,77 @@ func (nr *nodeRef) Value() Value {
 	return nil
 }
 
+// Prefix returns the full prefix of the inner node, nil for leaf nodes.
+func (nr *nodeRef) Prefix() Key {
+	if nr.isLeaf() {
+		return nil
+	}
+
+	path := nr.Path()
+
+	return path[len(path)-nr.PrefixLen():]
+}
+
+// PrefixLen returns the prefix length of the inner node, 0 for leaf nodes.
+func (nr *nodeRef) PrefixLen() int {
+	if nr.isLeaf() {
+		return 0
+	}
+
+	return int(nr.node().prefixLen)
+}
+
+// Depth returns the key offset where the node's prefix starts, the key length for leaf nodes.
+func (nr *nodeRef) Depth() int {
+	return len(nr.Path()) - nr.PrefixLen()
+}
+
+// ChildCount returns the number of children including the zero byte child.
+func (nr *nodeRef) ChildCount() int {
+	if nr.isLeaf() {
+		return 0
+	}
+
+	count := int(nr.node().childrenLen)
+	if nr.zeroChild() != nil {
+		count++
+	}
+
+	return count
+}
+
+// Children returns the zero byte child followed by the other children in ascending key order.
+func (nr *nodeRef) Children() []Child {
+	if nr.isLeaf() {
+		return nil
+	}
+
+	children := make([]Child, 0, nr.ChildCount())
+	if zc := nr.zeroChild(); zc != nil {
+		children = append(children, Child{Terminator: true, Node: zc})
+	}
+
+	nr.forEachChild(func(ch byte, child *nodeRef) {
+		children = append(children, Child{Key: ch, Node: child})
+	})
+
+	return children
+}
+
+// Path returns the key bytes from the root up to the end of the node's prefix.
+// All keys under an inner node share exactly these bytes, so it is
+// the longest common prefix of the minimum and maximum leaf keys.
+func (nr *nodeRef) Path() Key {
+	minKey := nr.minimum().key
+	if nr.isLeaf() {
+		return minKey
+	}
+
+	pathLen := findLongestCommonPrefix(minKey, nr.maximum().key, 0)
+
+	return minKey[:pathLen:pathLen]
+}
+
 // isLeaf returns true if the node is a leaf node.
 func (nr *nodeRef) isLeaf() bool {
 	return nr.kind == Leaf
@@ -142,7 +214,7 @@ func (nr *nodeRef) isLeaf() bool {
 func (nr *nodeRef) setPrefix(newPrefix []byte, prefixLen int) {
 	n := nr.node()
 
-	n.prefixLen = uint16(prefixLen) //#nosec:G115
+	n.prefixLen = uint32(prefixLen) //#nosec:G115
 	for i := 0; i < minInt(prefixLen, maxPrefixLen); i++ {
 		n.prefix[i] = newPrefix[i]
 	}
@@ -176,6 +248,162 @@ func (nr *nodeRef) findChildByKey(key Key, keyOffset int) **nodeRef {
 	return n.childAt(idx)
 }
 
+// zeroChild returns the zero byte child, i.e. the child for the key that ends at the node.
+func (nr *nodeRef) zeroChild() *nodeRef {
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		return nr.node4().children[node4Max]
+	case Node16:
+		return nr.node16().children[node16Max]
+	case Node48:
+		return nr.node48().children[node48Max]
+	case Node256:
+		return nr.node256().children[node256Max]
+	default:
+		return nil
+	}
+}
+
+// forEachChild calls fn for every child in ascending order of the child key bytes.
+// The zero byte child is not included, see zeroChild.
+func (nr *nodeRef) forEachChild(fn func(ch byte, child *nodeRef)) {
+	nr.rangeChildren(false, func(ch byte, child *nodeRef) bool {
+		fn(ch, child)
+
+		return true
+	})
+}
+
+// rangeChildren calls fn for every child in ascending or descending (reverse) order
+// of the child key bytes until fn returns false. The zero byte child is not included.
+// It returns false if the iteration was stopped by fn.
+func (nr *nodeRef) rangeChildren(reverse bool, fn func(ch byte, child *nodeRef) bool) bool {
+	var (
+		keys     []byte
+		children []*nodeRef
+	)
+
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		n := nr.node4()
+		keys, children = n.keys[:n.childrenLen], n.children[:n.childrenLen]
+	case Node16:
+		n := nr.node16()
+		keys, children = n.keys[:n.childrenLen], n.children[:n.childrenLen]
+	case Node48, Node256:
+		return nr.rangeByteChildren(reverse, fn)
+	default:
+		return true
+	}
+
+	for i := range children {
+		idx := ternary(reverse, len(children)-1-i, i)
+		if !fn(keys[idx], children[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rangeByteChildren is rangeChildren for node48 and node256, which are indexed by the key byte.
+func (nr *nodeRef) rangeByteChildren(reverse bool, fn func(ch byte, child *nodeRef) bool) bool {
+	for i := 0; i < node256Max; i++ {
+		ch := ternary(reverse, node256Max-1-i, i)
+
+		var child *nodeRef
+
+		if nr.kind == Node48 {
+			if n := nr.node48(); n.hasChild(ch) {
+				child = n.children[n.keys[ch]]
+			}
+		} else {
+			child = nr.node256().children[ch]
+		}
+
+		if child != nil && !fn(byte(ch), child) { //#nosec:G115
+			return false
+		}
+	}
+
+	return true
+}
+
+// maxChildOrdinal returns the largest child ordinal of the node, see childAtOrdinal.
+func (nr *nodeRef) maxChildOrdinal() int {
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		return node4Max
+	case Node16:
+		return node16Max
+	case Node48, Node256:
+		return node256Max
+	default:
+		return -1
+	}
+}
+
+// childAtOrdinal returns the child at the ordinal or nil.
+// The ordinals number the child positions in ascending order: 0 is the zero byte child,
+// the following ordinals are the children array indexes for node4 and node16,
+// and the key bytes for node48 and node256, shifted by one.
+func (nr *nodeRef) childAtOrdinal(ord int) *nodeRef {
+	if ord == 0 {
+		return nr.zeroChild()
+	}
+
+	idx := ord - 1
+
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		return nr.node4().children[idx]
+	case Node16:
+		return nr.node16().children[idx]
+	case Node48:
+		if n := nr.node48(); n.hasChild(idx) {
+			return n.children[n.keys[idx]]
+		}
+	case Node256:
+		return nr.node256().children[idx]
+	}
+
+	return nil
+}
+
+// ordinalOf returns the ordinal of the first child position with the key byte greater than or equal to ch.
+func (nr *nodeRef) ordinalOf(ch byte) int {
+	var keys []byte
+
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		n := nr.node4()
+		keys = n.keys[:n.childrenLen]
+	case Node16:
+		n := nr.node16()
+		keys = n.keys[:n.childrenLen]
+	default:
+		return int(ch) + 1
+	}
+
+	idx := 0
+	for idx < len(keys) && keys[idx] < ch {
+		idx++
+	}
+
+	return idx + 1
+}
+
+// ordinalOfChild returns the ordinal of the child or -1 if the node does not contain it.
+func (nr *nodeRef) ordinalOfChild(child *nodeRef) int {
+	for ord := 0; ord <= nr.maxChildOrdinal(); ord++ {
+		if nr.childAtOrdinal(ord) == child {
+			return ord
+		}
+	}
+
+	return -1
+}
+
 // nodeX/leaf casts the nodeRef to the specific nodeX/leaf type.
 func (nr *nodeRef) node() *node       { return (*node)(nr.ref) }    // node casts nodeRef to node.
 func (nr *nodeRef) node4() *node4     { return (*node4)(nr.ref) }   // node4 casts nodeRef to node4.
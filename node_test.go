@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test basic properties and behavior of each node kind.
@@ -81,7 +82,8 @@ func TestLeafMatchBehavior(t *testing.T) {
 	assert.True(t, leaf.leaf().match(Key("key")))
 
 	assert.False(t, leaf.leaf().prefixMatch(Key("unknown-key")))
-	assert.False(t, leaf.leaf().prefixMatch(nil))
+	assert.True(t, leaf.leaf().prefixMatch(nil))
+	assert.True(t, leaf.leaf().prefixMatch(Key{}))
 	assert.True(t, leaf.leaf().prefixMatch(Key("ke")))
 }
 
@@ -371,3 +373,75 @@ func TestNodeShrink(t *testing.T) {
 		})
 	}
 }
+
+func TestInnerNodeIntrospection(t *testing.T) {
+	t.Parallel()
+
+	tree := New()
+	for _, key := range []string{"api", "api/v1/users", "api/v1/users/1", "api/v1/users/2", "api/v2"} {
+		tree.Insert(Key(key), key)
+	}
+
+	type nodeInfo struct {
+		kind      Kind
+		path      string
+		prefix    string
+		depth     int
+		childKeys string
+	}
+
+	var nodes []nodeInfo
+
+	tree.ForEach(func(node Node) bool {
+		in, ok := node.(InnerNode)
+		require.True(t, ok)
+
+		childKeys := ""
+		for _, child := range in.Children() {
+			if child.Terminator {
+				childKeys += "$"
+			} else {
+				childKeys += string(child.Key)
+			}
+
+			assert.NotNil(t, child.Node)
+		}
+
+		assert.Equal(t, len(childKeys), in.ChildCount())
+		assert.Equal(t, len(in.Prefix()), in.PrefixLen())
+		nodes = append(nodes, nodeInfo{node.Kind(), string(in.Path()), string(in.Prefix()), in.Depth(), childKeys})
+
+		return true
+	}, TraverseAll)
+
+	assert.Equal(t, []nodeInfo{
+		{Node4, "api", "api", 0, "$/"},
+		{Leaf, "api", "", 3, ""},
+		{Node4, "api/v", "v", 4, "12"},
+		{Node4, "api/v1/users", "/users", 6, "$/"},
+		{Leaf, "api/v1/users", "", 12, ""},
+		{Node4, "api/v1/users/", "", 13, "12"},
+		{Leaf, "api/v1/users/1", "", 14, ""},
+		{Leaf, "api/v1/users/2", "", 14, ""},
+		{Leaf, "api/v2", "", 6, ""},
+	}, nodes)
+}
+
+func TestInnerNodeLongPrefix(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("prefix-longer-than-ten:1"), 1)
+	tree.Insert(Key("prefix-longer-than-ten:2"), 2)
+
+	root := tree.root
+	assert.Equal(t, Key("prefix-longer-than-ten:"), root.Prefix())
+	assert.Equal(t, 23, root.PrefixLen())
+	assert.Equal(t, 0, root.Depth())
+	assert.Equal(t, 2, root.ChildCount())
+
+	leaf := root.Children()[1].Node.(InnerNode)
+	assert.Nil(t, leaf.Prefix())
+	assert.Empty(t, leaf.Children())
+	assert.Equal(t, Key("prefix-longer-than-ten:2"), leaf.Path())
+}
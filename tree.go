@@ -50,10 +50,14 @@ type tree struct {
 	version int      // version is used to detect concurrent modifications
 	size    int      // size is the number of elements in the tree
 	root    *nodeRef // root is the root node of the tree
+	hooks   *Hooks   // hooks are the optional structural event callbacks
 }
 
-// make sure that tree implements all methods from the Tree interface.
-var _ Tree = (*tree)(nil)
+// make sure that tree implements all methods from the Tree and ReadOnlyTree interfaces.
+var (
+	_ Tree         = (*tree)(nil)
+	_ ReadOnlyTree = (*tree)(nil)
+)
 
 // Insert inserts the given key and value into the tree.
 // If the key already exists, it updates the value and
@@ -149,6 +153,12 @@ func (tr *tree) Size() int {
 // ForEach iterates over all keys in the tree and calls the callback function.
 func (tr *tree) ForEach(callback Callback, opts ...int) {
 	options := traverseOptions(opts...)
+	if options.hasResumable() || options.hasBreadthFirst() {
+		forEachNext(tr.Iterator(opts...), callback)
+
+		return
+	}
+
 	tr.forEachRecursively(tr.root, traverseFilter(options, callback), options.hasReverse())
 }
 
@@ -160,7 +170,16 @@ func (tr *tree) ForEachPrefix(key Key, callback Callback, opts ...int) {
 
 // Iterator returns a new tree iterator.
 func (tr *tree) Iterator(opts ...int) Iterator {
-	return newTreeIterator(tr, traverseOptions(opts...))
+	options := traverseOptions(opts...)
+	if options.hasResumable() {
+		return newResumableIterator(tr, options.hasReverse())
+	}
+
+	if options.hasBreadthFirst() {
+		return newBreadthFirstIterator(tr, options)
+	}
+
+	return newTreeIterator(tr, options)
 }
 
 // String returns tree in the human readable format, see DumpNode for examples.
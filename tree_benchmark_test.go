@@ -37,6 +37,19 @@ func BenchmarkWordsTreeSearch(b *testing.B) {
 	}
 }
 
+func BenchmarkWordsFrozenTreeSearch(b *testing.B) {
+	tree, words := treeWithData("test/assets/words.txt")
+	frozen := Freeze(tree)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, w := range words {
+			frozen.Search(w)
+		}
+	}
+}
+
 func BenchmarkWordsTreeIterator(b *testing.B) {
 	tree := New()
 
@@ -189,3 +202,17 @@ func BenchmarkHSKTreeForEach(b *testing.B) {
 	stats := collectStats(tree.Iterator(TraverseAll))
 	assert.Equal(b, treeStats{4995, 1630, 276, 21, 4}, stats)
 }
+
+// BenchmarkWordsTreeScanAllocs reports the allocations of a full scan, see TestIteratorAllocs.
+func BenchmarkWordsTreeScanAllocs(b *testing.B) {
+	tree, _ := treeWithData("test/assets/words.txt")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for it := tree.Iterator(TraverseAll); it.HasNext(); {
+			_, _ = it.Next()
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package art
+
+// levelNode is a node visited by the breadth-first traversal of the tree.
+type levelNode struct {
+	*nodeRef
+	level int
+}
+
+// assert that levelNode implements the LevelNode and InnerNode interfaces.
+var _ LevelNode = levelNode{}
+var _ InnerNode = levelNode{}
+
+// Level returns the number of edges between the root and the node.
+func (n levelNode) Level() int {
+	return n.level
+}
+
+// breadthFirstIterator iterates over the tree nodes level by level.
+type breadthFirstIterator struct {
+	version  int   // tree version at the time of iterator creation
+	tree     *tree // tree to iterate
+	opts     traverseOpts
+	queue    []levelNode // nodes to visit
+	nextNode Node        // next node to iterate
+	last     Node        // last returned node
+	stale    int         // number of queued nodes with the levels computed before the last collapse
+}
+
+// assert that breadthFirstIterator implements the Iterator interface.
+var _ Iterator = (*breadthFirstIterator)(nil)
+
+// newBreadthFirstIterator creates a new breadth-first tree iterator.
+func newBreadthFirstIterator(tr *tree, opts traverseOpts) *breadthFirstIterator {
+	it := &breadthFirstIterator{
+		version: tr.version,
+		tree:    tr,
+		opts:    opts,
+	}
+
+	if tr.root != nil {
+		it.queue = append(it.queue, levelNode{nodeRef: tr.root})
+	}
+
+	it.advance()
+
+	return it
+}
+
+// HasNext returns true if there are more nodes to iterate.
+func (it *breadthFirstIterator) HasNext() bool {
+	return it.nextNode != nil
+}
+
+// Next returns the next node and an error if any.
+// It returns ErrNoMoreNodes if there are no more nodes to iterate.
+// It returns ErrConcurrentModification if the tree has been modified concurrently.
+func (it *breadthFirstIterator) Next() (Node, error) {
+	if !it.HasNext() {
+		return nil, ErrNoMoreNodes
+	}
+
+	if it.version != it.tree.version {
+		return nil, ErrConcurrentModification
+	}
+
+	current := it.nextNode
+	it.last = current
+	it.advance()
+
+	return current, nil
+}
+
+// Remove deletes the leaf returned by the last call to Next from the tree.
+// The queued nodes stay valid, because the shrinking parent is replaced in place.
+// If the parent collapses into its only remaining child, the nodes below the parent
+// move one level up, so the levels of the queued nodes are recomputed.
+func (it *breadthFirstIterator) Remove() error {
+	if it.version != it.tree.version {
+		return ErrConcurrentModification
+	}
+
+	if it.last == nil || it.last.Kind() != Leaf {
+		return ErrNoLeafToRemove
+	}
+
+	key := it.last.Key()
+
+	var before nodeRef // the parent before the deletion
+	parent := it.tree.parentOf(key)
+	if parent != nil {
+		before = *parent
+	}
+
+	it.tree.Delete(key)
+	it.version = it.tree.version
+	it.last = nil
+
+	// only a node4 collapses, the other nodes shrink without changing the levels.
+	if before.kind == Node4 && parent.ref != before.ref {
+		it.stale = len(it.queue)
+
+		if next, ok := it.nextNode.(levelNode); ok {
+			next.level = it.tree.levelOf(next.nodeRef)
+			it.nextNode = next
+		}
+	}
+
+	return nil
+}
+
+// advance moves the iterator to the next node that matches the options.
+func (it *breadthFirstIterator) advance() {
+	for len(it.queue) > 0 {
+		current := it.queue[0]
+		it.queue = it.queue[1:]
+
+		if it.stale > 0 {
+			it.stale--
+			current.level = it.tree.levelOf(current.nodeRef)
+		}
+
+		if !current.isLeaf() {
+			ctx := newIteratorContext(current.nodeRef, it.opts.hasReverse())
+			for child, ok := ctx.next(); ok; child, ok = ctx.next() {
+				it.queue = append(it.queue, levelNode{nodeRef: child, level: current.level + 1})
+			}
+		}
+
+		if current.isLeaf() && it.opts.hasLeaf() || !current.isLeaf() && it.opts.hasNode() {
+			it.nextNode = current
+
+			return
+		}
+	}
+
+	it.nextNode = nil
+}
+
+// parentOf returns the inner node containing the leaf with the key as a child,
+// or nil if the leaf is the root. The leaf must exist.
+func (tr *tree) parentOf(key Key) *nodeRef {
+	var parent *nodeRef
+
+	keyOffset := 0
+
+	for current := tr.root; current != nil && !current.isLeaf(); keyOffset++ {
+		parent = current
+		keyOffset += int(current.node().prefixLen)
+		current = *current.findChildByKey(key, keyOffset)
+	}
+
+	return parent
+}
+
+// levelOf returns the number of edges between the root and the node.
+// The node is found by the reference, because the node collapsed into its parent
+// is referenced by both the parent and the detached child reference.
+func (tr *tree) levelOf(nr *nodeRef) int {
+	key := nr.minimum().key
+	level, keyOffset := 0, 0
+
+	for current := tr.root; current != nil && current.ref != nr.ref; level++ {
+		keyOffset += int(current.node().prefixLen)
+		current = *current.findChildByKey(key, keyOffset)
+		keyOffset++
+	}
+
+	return level
+}
+
+// frozenLevelNode is a node visited by the breadth-first traversal of the frozen tree.
+type frozenLevelNode struct {
+	Node
+	level int
+}
+
+// assert that frozenLevelNode implements the LevelNode interface.
+var _ LevelNode = frozenLevelNode{}
+
+// Level returns the number of edges between the root and the node.
+func (n frozenLevelNode) Level() int {
+	return n.level
+}
+
+// frozenLevelRef is a queued node of the frozen tree breadth-first traversal.
+type frozenLevelRef struct {
+	ref   frozenRef
+	level int
+}
+
+// frozenBreadthFirstIterator iterates over the frozen tree nodes level by level.
+type frozenBreadthFirstIterator struct {
+	tree     *frozenTree
+	opts     traverseOpts
+	queue    []frozenLevelRef
+	nextNode Node
+}
+
+// newFrozenBreadthFirstIterator creates a new breadth-first frozen tree iterator.
+func newFrozenBreadthFirstIterator(ft *frozenTree, opts traverseOpts) *frozenBreadthFirstIterator {
+	it := &frozenBreadthFirstIterator{
+		tree: ft,
+		opts: opts,
+	}
+
+	if ft.root != frozenNone {
+		it.queue = append(it.queue, frozenLevelRef{ref: ft.root})
+	}
+
+	it.advance()
+
+	return it
+}
+
+// HasNext returns true if there are more nodes to iterate.
+func (it *frozenBreadthFirstIterator) HasNext() bool {
+	return it.nextNode != nil
+}
+
+// Next returns the next node.
+// It returns ErrNoMoreNodes if there are no more nodes to iterate.
+func (it *frozenBreadthFirstIterator) Next() (Node, error) {
+	if !it.HasNext() {
+		return nil, ErrNoMoreNodes
+	}
+
+	current := it.nextNode
+	it.advance()
+
+	return current, nil
+}
+
+// Remove returns ErrReadOnlyTree, the frozen tree cannot be modified.
+func (it *frozenBreadthFirstIterator) Remove() error {
+	return ErrReadOnlyTree
+}
+
+// advance moves the iterator to the next node that matches the options.
+func (it *frozenBreadthFirstIterator) advance() {
+	for len(it.queue) > 0 {
+		current := it.queue[0]
+		it.queue = it.queue[1:]
+
+		if !current.ref.isLeaf() {
+			n := &it.tree.nodes[current.ref]
+			for i := 0; i <= int(n.childCount); i++ {
+				slot := ternary(it.opts.hasReverse(), int(n.childCount)-i, i)
+				if ref := it.tree.childAt(n, slot); ref != frozenNone {
+					it.queue = append(it.queue, frozenLevelRef{ref: ref, level: current.level + 1})
+				}
+			}
+		}
+
+		if current.ref.isLeaf() && it.opts.hasLeaf() || !current.ref.isLeaf() && it.opts.hasNode() {
+			it.nextNode = frozenLevelNode{Node: it.tree.nodeOf(current.ref), level: current.level}
+
+			return
+		}
+	}
+
+	it.nextNode = nil
+}
@@ -0,0 +1,236 @@
+package art
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// levelStep is a node visited by the breadth-first traversal.
+type levelStep struct {
+	Kind  Kind
+	Key   string
+	Level int
+}
+
+// collectLevels returns the nodes visited by ForEach with the given options.
+func collectLevels(t *testing.T, tree ReadOnlyTree, opts ...int) []levelStep {
+	t.Helper()
+
+	var steps []levelStep
+
+	tree.ForEach(func(node Node) bool {
+		ln, ok := node.(LevelNode)
+		require.True(t, ok)
+
+		steps = append(steps, levelStep{node.Kind(), string(node.Key()), ln.Level()})
+
+		return true
+	}, opts...)
+
+	return steps
+}
+
+func TestTreeBreadthFirst(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []int
+		want []levelStep
+	}{
+		{
+			name: "Leaf",
+			opts: []int{TraverseBreadthFirst},
+			want: []levelStep{{Leaf, "b", 1}, {Leaf, "c", 1}, {Leaf, "a", 2}, {Leaf, "aaa", 3}, {Leaf, "aab", 3}},
+		},
+		{
+			name: "LeafReverse",
+			opts: []int{TraverseBreadthFirst | TraverseReverse},
+			want: []levelStep{{Leaf, "c", 1}, {Leaf, "b", 1}, {Leaf, "a", 2}, {Leaf, "aab", 3}, {Leaf, "aaa", 3}},
+		},
+		{
+			name: "Node",
+			opts: []int{TraverseBreadthFirst, TraverseNode},
+			want: []levelStep{{Node4, "", 0}, {Node4, "", 1}, {Node4, "", 2}},
+		},
+		{
+			name: "All",
+			opts: []int{TraverseBreadthFirst | TraverseAll},
+			want: []levelStep{
+				{Node4, "", 0},
+				{Node4, "", 1}, {Leaf, "b", 1}, {Leaf, "c", 1},
+				{Leaf, "a", 2}, {Node4, "", 2},
+				{Leaf, "aaa", 3}, {Leaf, "aab", 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree := treeWithKeys("aaa", "aab", "b", "c", "a")
+			assert.Equal(t, tt.want, collectLevels(t, tree, tt.opts...))
+			assert.Equal(t, tt.want, collectLevels(t, Freeze(tree), tt.opts...))
+		})
+	}
+}
+
+func TestTreeBreadthFirstStop(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("aaa", "aab", "b", "c", "a")
+
+	var keys []string
+
+	tree.ForEach(func(node Node) bool {
+		keys = append(keys, string(node.Key()))
+
+		return len(keys) < 2
+	}, TraverseBreadthFirst)
+
+	assert.Equal(t, []string{"b", "c"}, keys)
+}
+
+func TestTreeBreadthFirstEmpty(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	assert.Empty(t, collectLevels(t, tree, TraverseBreadthFirst|TraverseAll))
+	assert.Empty(t, collectLevels(t, Freeze(tree), TraverseBreadthFirst|TraverseAll))
+
+	it := tree.Iterator(TraverseBreadthFirst)
+	assert.False(t, it.HasNext())
+
+	node, err := it.Next()
+	assert.Nil(t, node)
+	assert.Equal(t, ErrNoMoreNodes, err)
+}
+
+func TestTreeBreadthFirstInnerNode(t *testing.T) {
+	t.Parallel()
+
+	it := treeWithKeys("aaa", "aab", "b", "c", "a").Iterator(TraverseBreadthFirst | TraverseNode)
+	require.True(t, it.HasNext())
+
+	node, err := it.Next()
+	require.NoError(t, err)
+
+	inner, ok := node.(InnerNode)
+	require.True(t, ok)
+	assert.Equal(t, 3, inner.ChildCount())
+}
+
+func TestTreeBreadthFirstConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("aaa", "aab", "b", "c", "a")
+
+	it := tree.Iterator(TraverseBreadthFirst)
+	require.True(t, it.HasNext())
+
+	tree.Insert(Key("d"), "d")
+
+	node, err := it.Next()
+	assert.Nil(t, node)
+	assert.Equal(t, ErrConcurrentModification, err)
+}
+
+func TestTreeBreadthFirstForEachModification(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("aaa", "aab", "b", "c", "a")
+
+	var keys []string
+
+	tree.ForEach(func(node Node) bool {
+		require.NotNil(t, node)
+		keys = append(keys, string(node.Key()))
+		tree.Insert(Key(fmt.Sprintf("new%d", len(keys))), nil)
+
+		return len(keys) < 1000
+	}, TraverseBreadthFirst)
+
+	// the iteration stops at the first node after the modification.
+	assert.Equal(t, []string{"b"}, keys)
+}
+
+func TestTreeBreadthFirstWords(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := treeWithData("test/assets/words.txt")
+
+	stats := collectStats(tree.Iterator(TraverseBreadthFirst | TraverseAll))
+	assert.Equal(t, treeStats{235886, 113419, 10433, 403, 1}, stats)
+
+	level := 0
+	tree.ForEach(func(node Node) bool {
+		ln, _ := node.(LevelNode)
+		require.GreaterOrEqual(t, ln.Level(), level)
+		level = ln.Level()
+
+		return true
+	}, TraverseBreadthFirst|TraverseAll)
+
+	stats = collectStats(Freeze(tree).Iterator(TraverseBreadthFirst | TraverseAll))
+	assert.Equal(t, treeStats{235886, 113419, 10433, 403, 1}, stats)
+}
+
+func TestTreeBreadthFirstRemoveCollapse(t *testing.T) {
+	t.Parallel()
+
+	// the node4 "b" collapses into the node4 "b2" after "b1" is removed,
+	// so "b2" moves to the level 1 and its leaves to the level 2.
+	tests := []struct {
+		name string
+		opts int
+		want []levelStep
+	}{
+		{
+			name: "All",
+			opts: TraverseBreadthFirst | TraverseAll,
+			want: []levelStep{{Node4, "", 0}, {Leaf, "a", 1}, {Node4, "", 1}, {Leaf, "b1", 2}, {Node4, "", 1}, {Leaf, "b2x", 2}, {Leaf, "b2y", 2}},
+		},
+		{
+			name: "Leaf",
+			opts: TraverseBreadthFirst,
+			want: []levelStep{{Leaf, "a", 1}, {Leaf, "b1", 2}, {Leaf, "b2x", 2}, {Leaf, "b2y", 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree := newTree()
+			for _, key := range []string{"a", "b1", "b2x", "b2y"} {
+				tree.Insert(Key(key), key)
+			}
+
+			var steps []levelStep
+
+			for it := tree.Iterator(tt.opts); it.HasNext(); {
+				node, err := it.Next()
+				require.NoError(t, err)
+
+				ln, ok := node.(LevelNode)
+				require.True(t, ok)
+
+				steps = append(steps, levelStep{node.Kind(), string(node.Key()), ln.Level()})
+
+				if string(node.Key()) == "b1" {
+					require.NoError(t, it.Remove())
+				}
+			}
+
+			assert.Equal(t, tt.want, steps)
+			assert.Equal(t, []levelStep{{Node4, "", 0}, {Leaf, "a", 1}, {Node4, "", 1}, {Leaf, "b2x", 2}, {Leaf, "b2y", 2}},
+				collectLevels(t, tree, TraverseBreadthFirst|TraverseAll))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package art
+
+import "context"
+
+// ForEachCtx iterates over the nodes in the tree until the callback returns an error or the context is done.
+func (tr *tree) ForEachCtx(ctx context.Context, callback ErrorCallback, opts ...int) error {
+	return forEachCtx(ctx, callback, func(cb Callback) {
+		tr.ForEach(cb, opts...)
+	})
+}
+
+// ForEachPrefixCtx iterates over the keys with the given prefix until the callback returns an error
+// or the context is done.
+func (tr *tree) ForEachPrefixCtx(ctx context.Context, key Key, callback ErrorCallback, opts ...int) error {
+	return forEachCtx(ctx, callback, func(cb Callback) {
+		tr.ForEachPrefix(key, cb, opts...)
+	})
+}
+
+// ForEachCtx iterates over the nodes in the frozen tree until the callback returns an error or the context is done.
+func (ft *frozenTree) ForEachCtx(ctx context.Context, callback ErrorCallback, opts ...int) error {
+	return forEachCtx(ctx, callback, func(cb Callback) {
+		ft.ForEach(cb, opts...)
+	})
+}
+
+// ForEachPrefixCtx iterates over the keys with the given prefix in the frozen tree
+// until the callback returns an error or the context is done.
+func (ft *frozenTree) ForEachPrefixCtx(ctx context.Context, key Key, callback ErrorCallback, opts ...int) error {
+	return forEachCtx(ctx, callback, func(cb Callback) {
+		ft.ForEachPrefix(key, cb, opts...)
+	})
+}
+
+// forEachCtx runs the forEach traversal with the callback adapted to stop on the first error
+// or when the context is done, and returns that error.
+func forEachCtx(ctx context.Context, callback ErrorCallback, forEach func(cb Callback)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+
+	done := ctx.Done()
+	forEach(func(node Node) bool {
+		select {
+		case <-done:
+			err = ctx.Err()
+
+			return false
+		default:
+		}
+
+		err = callback(node)
+
+		return err == nil
+	})
+
+	return err
+}
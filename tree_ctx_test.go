@@ -0,0 +1,117 @@
+package art
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeForEachCtx(t *testing.T) {
+	t.Parallel()
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		tree := tree
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var keys []string
+
+			err := tree.ForEachCtx(context.Background(), func(node Node) error {
+				keys = append(keys, string(node.Key()))
+
+				return nil
+			}, TraverseReverse)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"c", "ba", "b", "abc", "ab", "a"}, keys)
+
+			keys = nil
+			err = tree.ForEachPrefixCtx(context.Background(), Key("ab"), func(node Node) error {
+				keys = append(keys, string(node.Key()))
+
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ab", "abc"}, keys)
+		})
+	}
+}
+
+func TestTreeForEachCtxError(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		tree := tree
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var keys []string
+
+			callback := func(node Node) error {
+				keys = append(keys, string(node.Key()))
+				if len(keys) == 2 {
+					return errStop
+				}
+
+				return nil
+			}
+
+			err := tree.ForEachCtx(context.Background(), callback)
+			assert.ErrorIs(t, err, errStop)
+			assert.Equal(t, []string{"a", "ab"}, keys)
+
+			keys = nil
+			err = tree.ForEachPrefixCtx(context.Background(), Key("b"), callback, TraverseReverse)
+			assert.ErrorIs(t, err, errStop)
+			assert.Equal(t, []string{"ba", "b"}, keys)
+		})
+	}
+}
+
+func TestTreeForEachCtxCanceled(t *testing.T) {
+	t.Parallel()
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		tree := tree
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var keys []string
+
+			err := tree.ForEachCtx(ctx, func(node Node) error {
+				keys = append(keys, string(node.Key()))
+				if len(keys) == 3 {
+					cancel()
+				}
+
+				return nil
+			})
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, []string{"a", "ab", "abc"}, keys)
+
+			// the context is already canceled, the callback is not called.
+			err = tree.ForEachPrefixCtx(ctx, Key("a"), func(Node) error {
+				t.Fatal("unexpected callback")
+
+				return nil
+			})
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+func TestTreeForEachCtxDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	err := newTree().ForEachCtx(ctx, func(Node) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
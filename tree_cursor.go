@@ -0,0 +1,197 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// cursor is a bidirectional position in the tree leaves.
+type cursor struct {
+	tree    *tree
+	version int             // tree version at the time of the last positioning
+	stack   []traverseFrame // inner nodes on the path to the current leaf
+	leaf    *nodeRef        // current leaf, nil if the cursor is not valid
+}
+
+// assert that cursor implements the Cursor interface.
+var _ Cursor = (*cursor)(nil)
+
+// NewCursor returns a new bidirectional cursor over the leaves of the tree,
+// it is not valid until positioned by First, Last or Seek.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func NewCursor(t Tree) (Cursor, error) {
+	tr, ok := t.(*tree)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	return tr.newCursor(), nil
+}
+
+// newCursor returns a new cursor, it is not valid until positioned by First, Last or Seek.
+func (tr *tree) newCursor() Cursor {
+	return &cursor{tree: tr}
+}
+
+// Valid returns true if the cursor is positioned at a leaf.
+func (c *cursor) Valid() bool {
+	return c.leaf != nil
+}
+
+// Key returns the key of the current leaf or nil.
+func (c *cursor) Key() Key {
+	if c.leaf == nil {
+		return nil
+	}
+
+	return c.leaf.leaf().key
+}
+
+// Value returns the value of the current leaf or nil.
+func (c *cursor) Value() Value {
+	if c.leaf == nil {
+		return nil
+	}
+
+	return c.leaf.leaf().value
+}
+
+// First moves the cursor to the smallest key.
+func (c *cursor) First() bool {
+	c.reset()
+
+	return c.descend(c.tree.root, true)
+}
+
+// Last moves the cursor to the largest key.
+func (c *cursor) Last() bool {
+	c.reset()
+
+	return c.descend(c.tree.root, false)
+}
+
+// Seek moves the cursor to the smallest key greater than or equal to the key.
+func (c *cursor) Seek(key Key) bool {
+	return c.seek(key, true)
+}
+
+// Next moves the cursor to the next key.
+func (c *cursor) Next() bool {
+	return c.move(true)
+}
+
+// Prev moves the cursor to the previous key.
+func (c *cursor) Prev() bool {
+	return c.move(false)
+}
+
+// move moves the cursor to the next or previous key.
+// If the tree has been modified, the cursor is re-positioned relative to the current key first.
+func (c *cursor) move(forward bool) bool {
+	if c.leaf == nil {
+		return false
+	}
+
+	if c.version != c.tree.version {
+		key := c.Key()
+		if !c.seek(key, forward) || !bytes.Equal(c.Key(), key) {
+			return c.Valid() // the current key has been deleted, the cursor is at its successor
+		}
+	}
+
+	return c.advance(forward)
+}
+
+// advance moves the cursor from the current stack position to the next or previous leaf.
+func (c *cursor) advance(forward bool) bool {
+	for len(c.stack) > 0 {
+		top := &c.stack[len(c.stack)-1]
+		if child := top.step(forward); child != nil {
+			return c.descend(child, forward)
+		}
+
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+
+	c.leaf = nil
+
+	return false
+}
+
+// reset clears the cursor position.
+func (c *cursor) reset() {
+	c.version = c.tree.version
+	c.stack = c.stack[:0]
+	c.leaf = nil
+}
+
+// descend moves the cursor to the first (or last) leaf of the subtree.
+func (c *cursor) descend(nr *nodeRef, forward bool) bool {
+	for nr != nil && !nr.isLeaf() {
+		c.stack = append(c.stack, newTraverseFrame(nr, forward))
+		nr = c.stack[len(c.stack)-1].step(forward)
+	}
+
+	c.leaf = nr
+
+	return nr != nil
+}
+
+// seek moves the cursor to the smallest key greater than or equal to the key,
+// or the largest key less than or equal to the key if forward is false.
+func (c *cursor) seek(key Key, forward bool) bool {
+	c.reset()
+
+	// before returns true if the key a is before the key b in the seek direction.
+	before := func(a, b Key) bool {
+		return ternary(forward, bytes.Compare(a, b) < 0, bytes.Compare(a, b) > 0)
+	}
+
+	first, last := (*nodeRef).minimum, (*nodeRef).maximum
+	if !forward {
+		first, last = last, first
+	}
+
+	keyOffset := 0
+
+	for nr := c.tree.root; nr != nil; {
+		if before(last(nr).key, key) {
+			// the whole subtree is before the key, continue with the next sibling.
+			return c.advance(forward)
+		}
+
+		if !before(first(nr).key, key) {
+			return c.descend(nr, forward)
+		}
+
+		// the subtree contains the keys on both sides of the key,
+		// so the node path is a prefix of the key.
+		keyOffset += int(nr.node().prefixLen)
+
+		frame := traverseFrame{nr: nr}
+
+		switch {
+		case keyOffset >= len(key):
+			// the zero child is the key itself.
+			frame.ord = ternary(forward, -1, 1)
+		case forward:
+			frame.ord = nr.ordinalOf(key[keyOffset]) - 1
+		default:
+			frame.ord = nr.maxChildOrdinal() + 1
+			if ch := key[keyOffset]; ch < 0xff {
+				frame.ord = nr.ordinalOf(ch + 1)
+			}
+		}
+
+		c.stack = append(c.stack, frame)
+		nr = c.stack[len(c.stack)-1].step(forward)
+		keyOffset++
+
+		if nr == nil {
+			// all children are before the key.
+			return c.advance(forward)
+		}
+	}
+
+	return false
+}
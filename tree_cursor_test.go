@@ -0,0 +1,237 @@
+package art
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorTestKeys returns the sorted keys that make a tree with a zero byte child,
+// a long prefix and nodes of all kinds.
+func cursorTestKeys() []string {
+	keys := []string{"", "a", "aa", "ab", "abcdefghijklmnopqrstuvwxyz", "abcdefghijklmnopqrstuvwxz", "b", "c\xff"}
+	for i := 0; i < 20; i++ {
+		keys = append(keys, "d"+string(rune('a'+i)))
+	}
+
+	for i := 0; i < 60; i++ {
+		keys = append(keys, "e"+string([]byte{byte(i * 4)}))
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// cursorKey returns the current cursor key as a string and "<invalid>" if the cursor is not valid.
+func cursorKey(c Cursor) string {
+	if !c.Valid() {
+		return "<invalid>"
+	}
+
+	return string(c.Key())
+}
+
+func TestTreeCursorForwardBackward(t *testing.T) {
+	t.Parallel()
+
+	keys := cursorTestKeys()
+	tree := treeWithKeys(keys...)
+	c := tree.newCursor()
+	assert.False(t, c.Valid())
+	assert.False(t, c.Next())
+	assert.False(t, c.Prev())
+
+	var got []string
+	for ok := c.First(); ok; ok = c.Next() {
+		got = append(got, string(c.Key()))
+		assert.Equal(t, string(c.Key()), c.Value())
+	}
+
+	assert.Equal(t, keys, got)
+	assert.False(t, c.Valid())
+	assert.Nil(t, c.Key())
+	assert.Nil(t, c.Value())
+
+	got = nil
+	for ok := c.Last(); ok; ok = c.Prev() {
+		got = append([]string{string(c.Key())}, got...)
+	}
+
+	assert.Equal(t, keys, got)
+}
+
+func TestTreeCursorChangeDirection(t *testing.T) {
+	t.Parallel()
+
+	keys := cursorTestKeys()
+	tree := treeWithKeys(keys...)
+	c := tree.newCursor()
+
+	require.True(t, c.First())
+
+	for i := 1; i < len(keys); i++ {
+		require.True(t, c.Next())
+		require.True(t, c.Prev())
+		assert.Equal(t, keys[i-1], cursorKey(c))
+		require.True(t, c.Next())
+		assert.Equal(t, keys[i], cursorKey(c))
+	}
+
+	assert.False(t, c.Next())
+
+	require.True(t, c.Last())
+
+	for i := len(keys) - 2; i >= 0; i-- {
+		require.True(t, c.Prev())
+		require.True(t, c.Next())
+		assert.Equal(t, keys[i+1], cursorKey(c))
+		require.True(t, c.Prev())
+		assert.Equal(t, keys[i], cursorKey(c))
+	}
+
+	assert.False(t, c.Prev())
+}
+
+func TestTreeCursorSeek(t *testing.T) {
+	t.Parallel()
+
+	keys := cursorTestKeys()
+	tree := treeWithKeys(keys...)
+	c := tree.newCursor()
+
+	// seek every existing key, the keys between them and the keys past the end.
+	var seeks []string
+	for _, key := range keys {
+		seeks = append(seeks, key, key+"\x00", key+"\xff", key[:len(key)/2]+"m")
+	}
+
+	seeks = append(seeks, "\xff", "abcdefghijklmnopqrstuvwxy", "abcdefghijklmnopqrstuvwxyz0", "e\x01", "e\xff")
+
+	for _, seek := range seeks {
+		idx := sort.SearchStrings(keys, seek)
+		if idx == len(keys) {
+			assert.False(t, c.Seek(Key(seek)), seek)
+			assert.False(t, c.Valid(), seek)
+
+			continue
+		}
+
+		require.True(t, c.Seek(Key(seek)), seek)
+		assert.Equal(t, keys[idx], cursorKey(c), seek)
+
+		if idx > 0 {
+			require.True(t, c.Prev(), seek)
+			assert.Equal(t, keys[idx-1], cursorKey(c), seek)
+		}
+	}
+}
+
+func TestTreeCursorEmptyAndLeaf(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	c := tree.newCursor()
+	assert.False(t, c.First())
+	assert.False(t, c.Last())
+	assert.False(t, c.Seek(Key("a")))
+	assert.False(t, c.Valid())
+
+	tree.Insert(Key("a"), 1)
+
+	require.True(t, c.Seek(nil))
+	assert.Equal(t, "a", cursorKey(c))
+	assert.Equal(t, 1, c.Value())
+	assert.False(t, c.Prev())
+	assert.True(t, c.Last())
+	assert.False(t, c.Next())
+	assert.False(t, c.Seek(Key("b")))
+}
+
+func TestTreeCursorModification(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys(cursorTestKeys()...)
+	c := tree.newCursor()
+
+	require.True(t, c.Seek(Key("da")))
+	tree.Insert(Key("d"), "d")
+	tree.Insert(Key("daa"), "daa")
+
+	require.True(t, c.Next())
+	assert.Equal(t, "daa", cursorKey(c))
+	require.True(t, c.Prev())
+	assert.Equal(t, "da", cursorKey(c))
+	require.True(t, c.Prev())
+	assert.Equal(t, "d", cursorKey(c))
+
+	// delete the current key, the cursor moves to its neighbor in the direction of the move.
+	tree.Delete(Key("d"))
+	require.True(t, c.Next())
+	assert.Equal(t, "da", cursorKey(c))
+
+	tree.Delete(Key("da"))
+	require.True(t, c.Prev())
+	assert.Equal(t, "c\xff", cursorKey(c))
+
+	// delete everything after the current key.
+	for _, key := range []string{"daa", "db", "dc"} {
+		tree.Delete(Key(key))
+	}
+
+	require.True(t, c.Next())
+	assert.Equal(t, "dd", cursorKey(c))
+}
+
+func TestTreeCursorWords(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := treeWithData("test/assets/words.txt")
+
+	var keys []string
+
+	tree.ForEach(func(node Node) bool {
+		keys = append(keys, string(node.Key()))
+
+		return true
+	})
+
+	c := tree.newCursor()
+	i := 0
+
+	for ok := c.First(); ok; ok = c.Next() {
+		require.Equal(t, keys[i], string(c.Key()))
+		i++
+	}
+
+	assert.Equal(t, len(keys), i)
+
+	for ok := c.Last(); ok; ok = c.Prev() {
+		i--
+		require.Equal(t, keys[i], string(c.Key()))
+	}
+
+	assert.Equal(t, 0, i)
+
+	for i := 0; i < len(keys); i += 997 {
+		require.True(t, c.Seek(Key(keys[i])))
+		require.Equal(t, keys[i], string(c.Key()))
+	}
+}
+
+func TestNewCursor(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCursor(treeWithKeys("a", "b"))
+	require.NoError(t, err)
+	require.True(t, c.Last())
+	assert.Equal(t, "b", string(c.Key()))
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{New()}
+
+	_, err = NewCursor(wrapped)
+	assert.ErrorIs(t, err, ErrUnsupportedTree)
+}
@@ -2,7 +2,7 @@ package art
 
 // deleteRecursively removes a node associated with the key from the tree.
 func (tr *tree) deleteRecursively(nrp **nodeRef, key Key, keyOffset int) (Value, treeOpResult) {
-	if tr == nil || *nrp == nil || len(key) == 0 {
+	if tr == nil || *nrp == nil {
 		return nil, treeOpNoChange
 	}
 
@@ -56,7 +56,10 @@ func (tr *tree) handleDeletionInChild(curNR, nextNR *nodeRef, key Key, keyOffset
 		return nil, treeOpNoChange
 	}
 
-	curNR.deleteChild(key.charAt(keyOffset))
+	kind := curNR.kind
+	if curNR.deleteChild(key.charAt(keyOffset)) {
+		tr.hooks.shrink(kind, curNR.kind)
+	}
 
 	return leaf.value, treeOpDeleted
 }
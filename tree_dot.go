@@ -0,0 +1,174 @@
+package art
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteDOT writes the tree structure as a Graphviz DOT graph.
+// Inner nodes are labeled with their kind and prefix, leaves with their key and value,
+// and edges with the child key byte. The zero byte (terminator) child edge is dashed.
+// It accepts the TreeStringer options except WithStorageSize and WithMaxChildren,
+// the leaf values are formatted with fmt.Sprint unless WithValueFormatter is given.
+// The tree must be of type *art.tree.
+//
+// The output can be rendered with: dot -Tsvg tree.dot -o tree.svg.
+func WriteDOT(w io.Writer, t Tree, opts ...DumpOption) error {
+	tr, ok := t.(*tree)
+	if !ok {
+		return fmt.Errorf("WriteDOT: expected *art.tree, got %T", t)
+	}
+
+	options := createTreeStringerOptions(opts...)
+	if options.valueFormatter == nil {
+		options.valueFormatter = func(v Value) string { return fmt.Sprint(v) }
+	}
+
+	if options.keyFormatter == nil {
+		options.keyFormatter = func(k Key) string { return strconv.Quote(string(k)) }
+	}
+
+	dw := &dotWriter{
+		w:    bufio.NewWriter(w),
+		opts: options,
+		registry: &nodeRegistry{
+			ptrToID:   make(map[*nodeRef]int),
+			formatter: options.formatter,
+		},
+	}
+
+	dw.printf("digraph art {\n")
+	dw.printf("\tnode [fontname=\"monospace\"];\n")
+	dw.printf("\tedge [fontname=\"monospace\"];\n")
+
+	if start := findPrefixNode(tr.root, options.prefix); start != nil {
+		dw.node(start, 0)
+	}
+
+	dw.printf("}\n")
+
+	if dw.err != nil {
+		return dw.err
+	}
+
+	return dw.w.Flush()
+}
+
+// findPrefixNode returns the topmost node containing only the keys with the given prefix.
+func findPrefixNode(nr *nodeRef, prefix Key) *nodeRef {
+	keyOffset := 0
+
+	for nr != nil {
+		if nr.isLeaf() {
+			if bytes.HasPrefix(nr.leaf().key, prefix) {
+				return nr
+			}
+
+			return nil
+		}
+
+		keyOffset += int(nr.node().prefixLen)
+		if keyOffset >= len(prefix) {
+			if bytes.HasPrefix(nr.minimum().key, prefix) {
+				return nr
+			}
+
+			return nil
+		}
+
+		nr = *nr.findChildByKey(prefix, keyOffset)
+		keyOffset++
+	}
+
+	return nil
+}
+
+// dotWriter writes the DOT graph and keeps the first write error.
+type dotWriter struct {
+	w        *bufio.Writer
+	opts     treeStringerOptions
+	registry *nodeRegistry
+	err      error
+}
+
+// printf writes the formatted string unless a previous write failed.
+func (dw *dotWriter) printf(format string, args ...interface{}) {
+	if dw.err == nil {
+		_, dw.err = fmt.Fprintf(dw.w, format, args...)
+	}
+}
+
+// ref returns the formatted reference of the node.
+func (dw *dotWriter) ref(nr *nodeRef) string {
+	return dw.registry.register(nr).String()
+}
+
+// id returns the quoted graph identifier of the node.
+func (dw *dotWriter) id(nr *nodeRef) string {
+	return strconv.Quote(dw.ref(nr))
+}
+
+// node writes the node, its edges and its children.
+func (dw *dotWriter) node(nr *nodeRef, depth int) {
+	id := dw.id(nr)
+
+	if nr.isLeaf() {
+		l := nr.leaf()
+		dw.printf("\t%s [shape=ellipse, label=\"%s\"];\n", id,
+			dotEscape(fmt.Sprintf("%s %s\nkey: %s\nvalue: %s",
+				Leaf, dw.ref(nr), dw.opts.keyFormatter(l.key), dw.opts.valueFormatter(l.value))))
+
+		return
+	}
+
+	n := nr.node()
+
+	prefix := strconv.Quote(string(n.prefix[:minInt(int(n.prefixLen), maxPrefixLen)]))
+	if n.prefixLen > maxPrefixLen {
+		prefix += "…"
+	}
+
+	dw.printf("\t%s [shape=box, label=\"%s\"];\n", id,
+		dotEscape(fmt.Sprintf("%s %s\nprefix(%d): %s", nr.kind, dw.ref(nr), n.prefixLen, prefix)))
+
+	if dw.opts.maxDepth > 0 && depth+1 >= dw.opts.maxDepth {
+		numChildren := int(n.childrenLen)
+		if nr.zeroChild() != nil {
+			numChildren++
+		}
+
+		more := strconv.Quote(dw.ref(nr) + "…")
+		dw.printf("\t%s [shape=plaintext, label=\"%d more\"];\n", more, numChildren)
+		dw.printf("\t%s -> %s [style=dotted];\n", id, more)
+
+		return
+	}
+
+	if zc := nr.zeroChild(); zc != nil {
+		dw.printf("\t%s -> %s [label=\"∅\", style=dashed];\n", id, dw.id(zc))
+		dw.node(zc, depth+1)
+	}
+
+	nr.forEachChild(func(ch byte, child *nodeRef) {
+		dw.printf("\t%s -> %s [label=\"%s\"];\n", id, dw.id(child), dotEscape(dotByte(ch)))
+		dw.node(child, depth+1)
+	})
+}
+
+// dotByte returns the edge label for the child key byte.
+func dotByte(ch byte) string {
+	if ch >= 0x20 && ch < 0x7f {
+		return strconv.QuoteRune(rune(ch))
+	}
+
+	return fmt.Sprintf("0x%02x", ch)
+}
+
+// dotEscape escapes the text for a double-quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
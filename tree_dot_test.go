@@ -0,0 +1,122 @@
+package art
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDOT(t *testing.T) {
+	t.Parallel()
+
+	// a tree with a zero byte child, a long prefix and a binary key.
+	tree := New()
+	for i, key := range []string{"a", "ab", "abc", "b", "prefix-longer-than-ten:1", "prefix-longer-than-ten:2", "x\x00\"y"} {
+		tree.Insert(Key(key), i)
+	}
+
+	tests := []struct {
+		name   string
+		tree   Tree
+		opts   []DumpOption
+		golden string
+	}{
+		{
+			name:   "Full",
+			tree:   tree,
+			golden: "test/dot/full.golden",
+		},
+		{
+			name:   "Prefix",
+			tree:   tree,
+			opts:   []DumpOption{WithPrefix(Key("pre"))},
+			golden: "test/dot/prefix.golden",
+		},
+		{
+			name:   "MaxDepth",
+			tree:   tree,
+			opts:   []DumpOption{WithMaxDepth(2)},
+			golden: "test/dot/max_depth.golden",
+		},
+		{
+			name: "ValueFormatter",
+			tree: tree,
+			opts: []DumpOption{
+				WithPrefix(Key("ab")),
+				WithValueFormatter(func(v Value) string { return fmt.Sprintf("<%03d>", v) }),
+			},
+			golden: "test/dot/value_formatter.golden",
+		},
+		{
+			name:   "Empty",
+			tree:   New(),
+			golden: "test/dot/empty.golden",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, WriteDOT(&buf, tt.tree, tt.opts...))
+
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(tt.golden, buf.Bytes(), 0o600))
+			}
+
+			goldenOut, err := os.ReadFile(tt.golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(goldenOut), buf.String())
+		})
+	}
+}
+
+func TestWriteDOTPrefixNotFound(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("abc", "prefix-longer-than-ten:1", "prefix-longer-than-ten:2")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDOT(&buf, tree, WithPrefix(Key("prefix-other"))))
+	assert.NotContains(t, buf.String(), "->")
+	assert.NotContains(t, buf.String(), "label")
+
+	buf.Reset()
+	require.NoError(t, WriteDOT(&buf, tree, WithPrefix(Key("abc"))))
+	assert.Equal(t, 1, strings.Count(buf.String(), "shape=ellipse"))
+	assert.Contains(t, buf.String(), `key: \"abc\"`)
+}
+
+func TestWriteDOTKeyFormatter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDOT(&buf, treeWithKeys("ab"), WithKeyFormatter(func(k Key) string { return fmt.Sprintf("%x", k) })))
+	assert.Contains(t, buf.String(), `key: 6162\nvalue: ab`)
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteDOTErrors(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("a", "b")
+	require.EqualError(t, WriteDOT(failingWriter{}, tree), "write failed")
+
+	type wrappedTree struct{ Tree }
+
+	require.Error(t, WriteDOT(&bytes.Buffer{}, wrappedTree{tree}))
+}
@@ -106,9 +106,10 @@ type depthStorage struct {
 
 // treeStringer is a helper struct for generating a human-readable representation of the tree.
 type treeStringer struct {
-	storage      []depthStorage // Storage for depth information
-	buf          *bytes.Buffer  // Buffer for building the string representation
-	nodeRegistry *nodeRegistry  // Registry for node references
+	storage      []depthStorage      // Storage for depth information
+	buf          *bytes.Buffer       // Buffer for building the string representation
+	nodeRegistry *nodeRegistry       // Registry for node references
+	opts         treeStringerOptions // Formatting and filtering options
 }
 
 // String returns the string representation of the tree.
@@ -283,6 +284,12 @@ func (ts *treeStringer) appendKey(keys []byte, present []byte, opts ...int) *tre
 
 // children generates a string representation of the children of a nodeRef.
 func (ts *treeStringer) children(children []*nodeRef, _ /*numChildred*/ uint16, keyOffset int, zeroChild *nodeRef) {
+	if ts.opts.maxChildren > 0 {
+		ts.elidedChildren(children, keyOffset, zeroChild)
+
+		return
+	}
+
 	for i, child := range children {
 		ts.baseNode(child, keyOffset, i, len(children)+1)
 	}
@@ -290,8 +297,50 @@ func (ts *treeStringer) children(children []*nodeRef, _ /*numChildred*/ uint16,
 	ts.baseNode(zeroChild, keyOffset, len(children)+1, len(children)+1)
 }
 
+// elidedChildren generates a string representation of at most maxChildren non-nil children
+// followed by the number of the elided children and the zero byte child.
+func (ts *treeStringer) elidedChildren(children []*nodeRef, depth int, zeroChild *nodeRef) {
+	shown, elided := ts.visibleChildren(children)
+
+	total := len(shown) + 1
+	if elided > 0 {
+		total++
+	}
+
+	for i, child := range shown {
+		ts.baseNode(child, depth, i, total)
+	}
+
+	childNum := len(shown)
+	if elided > 0 {
+		padHeader, _ := ts.generatePads(depth, childNum, total)
+		ts.append(padHeader).
+			append(fmt.Sprintf("… %d more children\n", elided))
+		childNum++
+	}
+
+	ts.baseNode(zeroChild, depth, childNum, total)
+}
+
+// visibleChildren returns at most maxChildren non-nil children and the number of the remaining ones.
+func (ts *treeStringer) visibleChildren(children []*nodeRef) (shown []*nodeRef, elided int) {
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+
+		if len(shown) < ts.opts.maxChildren {
+			shown = append(shown, child)
+		} else {
+			elided++
+		}
+	}
+
+	return shown, elided
+}
+
 // node generates a string representation of a nodeRef.
-func (ts *treeStringer) node(pad string, prefixLen uint16, prefix []byte, keys []byte, present []byte, children []*nodeRef, numChildren uint16, keyOffset int, zeroChild *nodeRef) {
+func (ts *treeStringer) node(pad string, prefixLen uint32, prefix []byte, keys []byte, present []byte, children []*nodeRef, numChildren uint16, keyOffset int, zeroChild *nodeRef) {
 	if prefix != nil {
 		ts.append(pad).
 			append(fmt.Sprintf("prefix(%x): ", prefixLen)).
@@ -310,11 +359,28 @@ func (ts *treeStringer) node(pad string, prefixLen uint16, prefix []byte, keys [
 			append("\n")
 	}
 
-	ts.append(pad).
-		append(fmt.Sprintf("children(%v): %+v <%v>\n",
-			numChildren,
-			ts.regNodes(children),
-			ts.regNode(zeroChild)))
+	if ts.opts.maxChildren > 0 {
+		shown, elided := ts.visibleChildren(children)
+		ts.append(pad).
+			append(fmt.Sprintf("children(%v): %+v", numChildren, ts.regNodes(shown)))
+		if elided > 0 {
+			ts.append(fmt.Sprintf(" +%d", elided))
+		}
+		ts.append(fmt.Sprintf(" <%v>\n", ts.regNode(zeroChild)))
+	} else {
+		ts.append(pad).
+			append(fmt.Sprintf("children(%v): %+v <%v>\n",
+				numChildren,
+				ts.regNodes(children),
+				ts.regNode(zeroChild)))
+	}
+
+	if ts.opts.maxDepth > 0 && keyOffset+1 >= ts.opts.maxDepth {
+		ts.append(pad).
+			append("…\n")
+
+		return
+	}
 
 	ts.children(children, numChildren, keyOffset+1, zeroChild)
 }
@@ -407,14 +473,22 @@ func (ts *treeStringer) baseNode(an *nodeRef, depth int, childNum int, childrenT
 	case Leaf:
 		n := an.leaf()
 
-		ts.append(pad).
-			append(fmt.Sprintf("key(%d): ", len(n.key))).
-			append(n.key).
-			append(" ").
-			append(fmt.Sprintf("%v", n.key)).
-			append("\n")
+		if ts.opts.keyFormatter != nil {
+			ts.append(pad).
+				append(fmt.Sprintf("key(%d): %s\n", len(n.key), ts.opts.keyFormatter(n.key)))
+		} else {
+			ts.append(pad).
+				append(fmt.Sprintf("key(%d): ", len(n.key))).
+				append(n.key).
+				append(" ").
+				append(fmt.Sprintf("%v", n.key)).
+				append("\n")
+		}
 
-		if s, ok := n.value.(string); ok {
+		if ts.opts.valueFormatter != nil {
+			ts.append(pad).
+				append(fmt.Sprintf("val: %s\n", ts.opts.valueFormatter(n.value)))
+		} else if s, ok := n.value.(string); ok {
 			ts.append(pad).
 				append(fmt.Sprintf("val: %v\n",
 					s))
@@ -500,41 +574,91 @@ func DumpNode(root *nodeRef) string {
 
 // treeStringerOptions contains options for DumpTree function.
 type treeStringerOptions struct {
-	storageSize int
-	formatter   refFormatter
+	storageSize    int
+	formatter      refFormatter
+	valueFormatter func(Value) string
+	keyFormatter   func(Key) string
+	maxDepth       int
+	prefix         Key
+	maxChildren    int
 }
 
-// treeStringerOption is a function that sets an option for DumpTree.
-type treeStringerOption func(opts *treeStringerOptions)
+// DumpOption is a function that sets an option for TreeStringer and WriteDOT.
+type DumpOption func(opts *treeStringerOptions)
 
-// WithStorageSize sets the size of the storage for depth information.
-func WithStorageSize(size int) treeStringerOption {
+// WithStorageSize sets the size of the storage for depth information, it is used by TreeStringer only.
+func WithStorageSize(size int) DumpOption {
 	return func(opts *treeStringerOptions) {
 		opts.storageSize = size
 	}
 }
 
 // WithRefFormatter sets the formatter for node references.
-func WithRefFormatter(formatter refFormatter) treeStringerOption {
+func WithRefFormatter(formatter refFormatter) DumpOption {
 	return func(opts *treeStringerOptions) {
 		opts.formatter = formatter
 	}
 }
 
+// WithValueFormatter sets the formatter for leaf values.
+func WithValueFormatter(formatter func(Value) string) DumpOption {
+	return func(opts *treeStringerOptions) {
+		opts.valueFormatter = formatter
+	}
+}
+
+// WithKeyFormatter sets the formatter for leaf keys.
+func WithKeyFormatter(formatter func(Key) string) DumpOption {
+	return func(opts *treeStringerOptions) {
+		opts.keyFormatter = formatter
+	}
+}
+
+// WithMaxDepth limits the output to depth levels of nodes, deeper nodes are replaced with "…".
+// Zero or negative depth means no limit.
+func WithMaxDepth(depth int) DumpOption {
+	return func(opts *treeStringerOptions) {
+		opts.maxDepth = depth
+	}
+}
+
+// WithPrefix limits the output to the smallest subtree containing all keys with the given prefix.
+func WithPrefix(prefix Key) DumpOption {
+	return func(opts *treeStringerOptions) {
+		opts.prefix = prefix
+	}
+}
+
+// WithMaxChildren limits the output to the first n non-nil children of every node,
+// the empty child slots are skipped and the rest of the children are replaced with their count.
+// Zero or negative n prints all child slots. It is used by TreeStringer only.
+func WithMaxChildren(n int) DumpOption {
+	return func(opts *treeStringerOptions) {
+		opts.maxChildren = n
+	}
+}
+
 // TreeStringer returns the string representation of the tree.
 // The tree must be of type *art.tree.
-func TreeStringer(t Tree, opts ...treeStringerOption) string {
+func TreeStringer(t Tree, opts ...DumpOption) string {
 	tr, ok := t.(*tree)
 	if !ok {
 		return "expected *art.tree"
 	}
 
-	trs := newTreeStringer(createTreeStringerOptions(opts...))
-	trs.startFromNode(tr.root)
+	options := createTreeStringerOptions(opts...)
+
+	root := tr.root
+	if options.prefix != nil {
+		root = findPrefixNode(root, options.prefix)
+	}
+
+	trs := newTreeStringer(options)
+	trs.startFromNode(root)
 	return trs.String()
 }
 
-func createTreeStringerOptions(opts ...treeStringerOption) treeStringerOptions {
+func createTreeStringerOptions(opts ...DumpOption) treeStringerOptions {
 	defOpts := treeStringerOptions{
 		storageSize: 4096,
 		formatter:   RefShortFormatter,
@@ -549,6 +673,7 @@ func createTreeStringerOptions(opts ...treeStringerOption) treeStringerOptions {
 
 func newTreeStringer(opts treeStringerOptions) *treeStringer {
 	return &treeStringer{
+		opts:    opts,
 		storage: make([]depthStorage, opts.storageSize),
 		buf:     bytes.NewBufferString(""),
 		nodeRegistry: &nodeRegistry{
@@ -2,6 +2,7 @@ package art
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"testing"
 
@@ -113,3 +114,69 @@ func TestTreeStringer(t *testing.T) {
 		})
 	}
 }
+
+func TestDumpOptions(t *testing.T) {
+	t.Parallel()
+
+	newOptionsTree := func() Tree {
+		tree := New()
+		tree.Insert(Key("a"), "a")
+
+		for i := 0; i < node48Max+1; i++ {
+			tree.Insert(Key{'a', byte(i)}, i)
+		}
+
+		tree.Insert(Key("prefix-1"), []byte("one"))
+		tree.Insert(Key("prefix-2"), []byte("two"))
+
+		return tree
+	}
+
+	tests := []struct {
+		name   string
+		opts   []DumpOption
+		golden string
+	}{
+		{
+			name:   "MaxDepth",
+			opts:   []DumpOption{WithMaxDepth(1)},
+			golden: "test/stringer/options_max_depth.golden",
+		},
+		{
+			name:   "MaxChildren",
+			opts:   []DumpOption{WithMaxChildren(2)},
+			golden: "test/stringer/options_max_children.golden",
+		},
+		{
+			name: "PrefixWithFormatters",
+			opts: []DumpOption{
+				WithPrefix(Key("pre")),
+				WithKeyFormatter(func(k Key) string { return fmt.Sprintf("%q", k) }),
+				WithValueFormatter(func(v Value) string { return fmt.Sprintf("%x", v) }),
+			},
+			golden: "test/stringer/options_prefix_formatters.golden",
+		},
+		{
+			name:   "PrefixNotFound",
+			opts:   []DumpOption{WithPrefix(Key("missing"))},
+			golden: "test/stringer/options_prefix_not_found.golden",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actualOut := TreeStringer(newOptionsTree(), tt.opts...)
+
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(tt.golden, []byte(actualOut), 0o600))
+			}
+
+			goldenOut, err := os.ReadFile(tt.golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(goldenOut), actualOut)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package art
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrefixCheck describes how the node prefix was compared with the search key.
+type PrefixCheck int
+
+// Prefix check types.
+const (
+	// PrefixCheckNone means that the node has no prefix.
+	PrefixCheckNone PrefixCheck = iota
+
+	// PrefixCheckPessimistic means that the whole prefix is stored in the node
+	// and it was compared with the key.
+	PrefixCheckPessimistic
+
+	// PrefixCheckOptimistic means that the prefix is longer than maxPrefixLen,
+	// only the stored bytes were compared and the rest was skipped.
+	// The skipped bytes are verified by the final leaf key comparison.
+	PrefixCheckOptimistic
+)
+
+// String returns string representation of the PrefixCheck value.
+func (c PrefixCheck) String() string {
+	return []string{"none", "pessimistic", "optimistic"}[c]
+}
+
+// ExplainResult describes why the lookup ended.
+type ExplainResult int
+
+// Lookup results.
+const (
+	// ExplainFound means that the leaf with the key was found.
+	ExplainFound ExplainResult = iota
+
+	// ExplainEmptyTree means that the tree has no nodes.
+	ExplainEmptyTree
+
+	// ExplainPrefixMismatch means that the stored prefix of an inner node does not match the key.
+	ExplainPrefixMismatch
+
+	// ExplainNoChild means that an inner node has no child for the next key byte.
+	ExplainNoChild
+
+	// ExplainLeafMismatch means that the lookup reached a leaf with a different key.
+	ExplainLeafMismatch
+)
+
+// String returns string representation of the ExplainResult value.
+func (r ExplainResult) String() string {
+	return []string{"found", "empty tree", "prefix mismatch", "no child", "leaf mismatch"}[r]
+}
+
+// ExplainStep describes a node visited by the lookup.
+type ExplainStep struct {
+	// Node is the visited node.
+	Node Node
+
+	// KeyOffset is the offset of the search key where the node is entered.
+	KeyOffset int
+
+	// PrefixLen is the length of the inner node prefix.
+	PrefixLen int
+
+	// PrefixCheck is the type of the prefix comparison.
+	PrefixCheck PrefixCheck
+
+	// PrefixMatched is the number of prefix bytes that matched the key.
+	PrefixMatched int
+
+	// PrefixSkipped is the number of prefix bytes skipped by the optimistic check.
+	PrefixSkipped int
+
+	// ChildKey is the key byte used to select the child.
+	ChildKey byte
+
+	// Terminator is true if the key ends at the node and its zero byte child was selected.
+	Terminator bool
+
+	// MismatchOffset is the first key offset where the leaf key differs from the search key,
+	// -1 if the leaf matches or the step is an inner node.
+	MismatchOffset int
+}
+
+// Explanation is the trace of a lookup returned by Explain.
+type Explanation struct {
+	// Key is the search key.
+	Key Key
+
+	// Steps are the visited nodes from the root.
+	Steps []ExplainStep
+
+	// Result describes why the lookup ended.
+	Result ExplainResult
+
+	// Value is the found value.
+	Value Value
+}
+
+// Explain returns the trace of the nodes visited by Search for the key,
+// including the prefix comparisons and the reason the lookup ended.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func Explain(t Tree, key Key) (Explanation, error) {
+	tr, ok := t.(*tree)
+	if !ok {
+		return Explanation{}, fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	return tr.explain(key), nil
+}
+
+// explain returns the sequence of nodes visited by Search for the key.
+// It follows the same algorithm as Search, including the optimistic prefix checks.
+func (tr *tree) explain(key Key) Explanation {
+	exp := Explanation{Key: key, Result: ExplainEmptyTree}
+	keyOffset := 0
+
+	current := tr.root
+	for current != nil {
+		step := ExplainStep{Node: current, KeyOffset: keyOffset, MismatchOffset: -1}
+
+		if current.isLeaf() {
+			leaf := current.leaf()
+			if leaf.match(key) {
+				exp.Result, exp.Value = ExplainFound, leaf.value
+			} else {
+				exp.Result = ExplainLeafMismatch
+				step.MismatchOffset = findLongestCommonPrefix(leaf.key, key, 0)
+			}
+
+			exp.Steps = append(exp.Steps, step)
+
+			return exp
+		}
+
+		curNode := current.node()
+		if curNode.prefixLen > 0 {
+			stored := minInt(int(curNode.prefixLen), maxPrefixLen)
+
+			step.PrefixLen = int(curNode.prefixLen)
+			step.PrefixCheck = ternary(stored < step.PrefixLen, PrefixCheckOptimistic, PrefixCheckPessimistic)
+			step.PrefixMatched = current.match(key, keyOffset)
+			step.PrefixSkipped = step.PrefixLen - stored
+
+			if step.PrefixMatched != stored {
+				exp.Steps = append(exp.Steps, step)
+				exp.Result = ExplainPrefixMismatch
+
+				return exp
+			}
+
+			keyOffset += step.PrefixLen
+		}
+
+		kc := key.charAt(keyOffset)
+		step.ChildKey, step.Terminator = kc.ch, kc.invalid
+		exp.Steps = append(exp.Steps, step)
+
+		current = *current.findChildByKey(key, keyOffset)
+		exp.Result = ExplainNoChild
+		keyOffset++
+	}
+
+	return exp
+}
+
+// String returns a human-readable trace of the lookup, one line per step.
+func (e Explanation) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "search %q: %s\n", e.Key, e.Result)
+
+	for i, step := range e.Steps {
+		fmt.Fprintf(&sb, "%d. %s at offset %d", i, step.Node.Kind(), step.KeyOffset)
+
+		if step.Node.Kind() == Leaf {
+			fmt.Fprintf(&sb, ": key %q", step.Node.Key())
+
+			if step.MismatchOffset >= 0 {
+				fmt.Fprintf(&sb, " differs at offset %d", step.MismatchOffset)
+			}
+
+			sb.WriteString("\n")
+
+			continue
+		}
+
+		if step.PrefixCheck != PrefixCheckNone {
+			fmt.Fprintf(&sb, ": %s prefix check, %d of %d bytes matched",
+				step.PrefixCheck, step.PrefixMatched, step.PrefixLen-step.PrefixSkipped)
+
+			if step.PrefixSkipped > 0 {
+				fmt.Fprintf(&sb, ", %d skipped", step.PrefixSkipped)
+			}
+		}
+
+		if i+1 < len(e.Steps) || e.Result == ExplainNoChild {
+			if step.Terminator {
+				sb.WriteString(", terminator child")
+			} else {
+				fmt.Fprintf(&sb, ", child %q", step.ChildKey)
+			}
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeExplain(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for _, key := range []string{"api", "api/v1/users/1", "api/v1/users/2", "b"} {
+		tree.Insert(Key(key), key)
+	}
+
+	exp := tree.explain(Key("api/v1/users/2"))
+	assert.Equal(t, ExplainFound, exp.Result)
+	assert.Equal(t, "api/v1/users/2", exp.Value)
+	require.Len(t, exp.Steps, 4)
+
+	// root: no prefix, child 'a'
+	assert.Equal(t, Node4, exp.Steps[0].Node.Kind())
+	assert.Equal(t, PrefixCheckNone, exp.Steps[0].PrefixCheck)
+	assert.Equal(t, byte('a'), exp.Steps[0].ChildKey)
+
+	// "pi": stored prefix, child '/'
+	assert.Equal(t, 1, exp.Steps[1].KeyOffset)
+	assert.Equal(t, PrefixCheckPessimistic, exp.Steps[1].PrefixCheck)
+	assert.Equal(t, 2, exp.Steps[1].PrefixMatched)
+	assert.Equal(t, byte('/'), exp.Steps[1].ChildKey)
+
+	// "v1/users/": 9 bytes, pessimistic
+	assert.Equal(t, 4, exp.Steps[2].KeyOffset)
+	assert.Equal(t, PrefixCheckPessimistic, exp.Steps[2].PrefixCheck)
+	assert.Equal(t, byte('2'), exp.Steps[2].ChildKey)
+
+	assert.Equal(t, Leaf, exp.Steps[3].Node.Kind())
+	assert.Equal(t, -1, exp.Steps[3].MismatchOffset)
+
+	assert.Equal(t, `search "api/v1/users/2": found
+0. Node4 at offset 0, child 'a'
+1. Node4 at offset 1: pessimistic prefix check, 2 of 2 bytes matched, child '/'
+2. Node4 at offset 4: pessimistic prefix check, 9 of 9 bytes matched, child '2'
+3. Leaf at offset 14: key "api/v1/users/2"
+`, exp.String())
+
+	exp = tree.explain(Key("api"))
+	assert.Equal(t, ExplainFound, exp.Result)
+	assert.True(t, exp.Steps[1].Terminator)
+
+	exp = tree.explain(Key("apx"))
+	assert.Equal(t, ExplainPrefixMismatch, exp.Result)
+	assert.Len(t, exp.Steps, 2)
+	assert.Equal(t, 1, exp.Steps[1].PrefixMatched)
+
+	exp = tree.explain(Key("c"))
+	assert.Equal(t, ExplainNoChild, exp.Result)
+	assert.Equal(t, `search "c": no child
+0. Node4 at offset 0, child 'c'
+`, exp.String())
+
+	exp = tree.explain(Key("bb"))
+	assert.Equal(t, ExplainLeafMismatch, exp.Result)
+	assert.Equal(t, 1, exp.Steps[1].MismatchOffset)
+
+	assert.Equal(t, ExplainEmptyTree, newTree().explain(Key("a")).Result)
+}
+
+func TestTreeExplainOptimisticPrefix(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("shared-prefix-longer-than-ten:1"), 1)
+	tree.Insert(Key("shared-prefix-longer-than-ten:2"), 2)
+
+	// the mismatch is beyond the stored prefix, so it is found only at the leaf.
+	exp := tree.explain(Key("shared-prefiX-longer-than-ten:1"))
+	assert.Equal(t, ExplainLeafMismatch, exp.Result)
+	require.Len(t, exp.Steps, 2)
+	assert.Equal(t, PrefixCheckOptimistic, exp.Steps[0].PrefixCheck)
+	assert.Equal(t, 10, exp.Steps[0].PrefixMatched)
+	assert.Equal(t, 20, exp.Steps[0].PrefixSkipped)
+	assert.Equal(t, 12, exp.Steps[1].MismatchOffset)
+
+	assert.Equal(t, `search "shared-prefiX-longer-than-ten:1": leaf mismatch
+0. Node4 at offset 0: optimistic prefix check, 10 of 10 bytes matched, 20 skipped, child '1'
+1. Leaf at offset 31: key "shared-prefix-longer-than-ten:1" differs at offset 12
+`, exp.String())
+
+	for _, key := range []string{"shared-prefix-longer-than-ten:1", "x", "", "shared"} {
+		exp := tree.explain(Key(key))
+		val, found := tree.Search(Key(key))
+		assert.Equal(t, found, exp.Result == ExplainFound, key)
+		assert.Equal(t, val, exp.Value, key)
+	}
+}
+
+func TestExplainTreeInterface(t *testing.T) {
+	t.Parallel()
+
+	exp, err := Explain(New(), Key("a"))
+	require.NoError(t, err)
+	assert.Equal(t, ExplainEmptyTree, exp.Result)
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{New()}
+
+	_, err = Explain(wrapped, Key("a"))
+	assert.ErrorIs(t, err, ErrUnsupportedTree)
+}
@@ -0,0 +1,486 @@
+package art
+
+import (
+	"bytes"
+	"math"
+	"sort"
+)
+
+// frozenRef references a node of the frozen tree.
+// Non-negative values are indexes of inner nodes,
+// negative values are bitwise complements of leaf indexes.
+type frozenRef int32
+
+// frozenNone is a special reference that indicates that there is no child.
+const frozenNone frozenRef = math.MinInt32
+
+// isLeaf returns true if the reference points to a leaf.
+func (ref frozenRef) isLeaf() bool {
+	return ref < 0
+}
+
+// leafIdx returns the index of the referenced leaf.
+func (ref frozenRef) leafIdx() int {
+	return int(^ref)
+}
+
+// frozenNode is an inner node of the frozen tree.
+// The children with key bytes are stored in the tree's childKeys/childRefs slabs
+// at [childStart, childStart+childCount) in ascending key order.
+// The node contains no pointers, so the GC does not need to scan the nodes slab.
+type frozenNode struct {
+	prefixLen  uint32
+	childStart uint32
+	zeroChild  frozenRef
+	prefix     prefix
+	childCount uint16
+	kind       uint8
+}
+
+// frozenLeaf is a leaf of the frozen tree, the location of its key in the tree's keys slab.
+// The leaf contains no pointers, so the GC does not need to scan the leaves slab.
+// The value of the leaf is stored in the tree's values slab at the same index.
+type frozenLeaf struct {
+	keyStart uint32
+	keyLen   uint32
+}
+
+// frozenTree is a compact read-only tree.
+// Inner nodes are packed in breadth-first order, leaves are stored in key order.
+type frozenTree struct {
+	nodes     []frozenNode
+	childKeys []byte
+	childRefs []frozenRef
+	leaves    []frozenLeaf
+	keys      []byte  // keys of all leaves
+	values    []Value // values of all leaves
+	root      frozenRef
+}
+
+// frozenLeafNode is the public Node of the frozen tree leaf.
+type frozenLeafNode struct {
+	tree *frozenTree
+	idx  int
+}
+
+// make sure that frozenTree implements all methods from the FrozenTree interface.
+var _ FrozenTree = (*frozenTree)(nil)
+
+// assert that frozen nodes and leaves implement public Node interface.
+var _ Node = (*frozenNode)(nil)
+var _ Node = frozenLeafNode{}
+
+// Kind returns the node kind.
+func (n *frozenNode) Kind() Kind { return Kind(n.kind) }
+
+// Key returns nil for inner nodes.
+func (n *frozenNode) Key() Key { return nil }
+
+// Value returns nil for inner nodes.
+func (n *frozenNode) Value() Value { return nil }
+
+// Kind returns Leaf.
+func (l frozenLeafNode) Kind() Kind { return Leaf }
+
+// Key returns the leaf key.
+func (l frozenLeafNode) Key() Key { return l.tree.key(l.idx) }
+
+// Value returns the leaf value.
+func (l frozenLeafNode) Value() Value { return l.tree.values[l.idx] }
+
+// key returns the key of the leaf with the index.
+func (ft *frozenTree) key(idx int) Key {
+	l := ft.leaves[idx]
+	end := l.keyStart + l.keyLen
+
+	return ft.keys[l.keyStart:end:end]
+}
+
+// Freeze returns a read-only copy of the tree packed into contiguous arrays.
+// The nodes and leaves of the frozen tree contain no pointers, so it puts almost no load on the GC.
+// Later modifications of the tree do not affect the frozen copy.
+// The trees that are not created by New or NewWithOptions are copied with ForEach first.
+func Freeze(t Tree) FrozenTree {
+	tr, ok := t.(*tree)
+	if !ok {
+		tr = newTree()
+		t.ForEach(func(node Node) bool {
+			tr.Insert(node.Key(), node.Value())
+
+			return true
+		})
+	}
+
+	return tr.freeze()
+}
+
+// freeze converts the tree into a frozen tree.
+func (tr *tree) freeze() FrozenTree {
+	ft := &frozenTree{root: frozenNone}
+	if tr.root == nil {
+		return ft
+	}
+
+	leafRefs := ft.freezeLeaves(tr)
+	ft.root = ft.refOf(tr.root, leafRefs, 0)
+
+	if tr.root.isLeaf() {
+		return ft
+	}
+
+	// pack inner nodes breadth-first, the index of a node is known when it is queued.
+	queue := []*nodeRef{tr.root}
+	for len(queue) > 0 {
+		nr := queue[0]
+		queue = queue[1:]
+
+		n := nr.node()
+		fn := frozenNode{
+			prefix:     n.prefix,
+			prefixLen:  n.prefixLen,
+			kind:       uint8(nr.kind),
+			childStart: uint32(len(ft.childKeys)), //#nosec:G115
+		}
+
+		if zc := nr.zeroChild(); zc != nil {
+			fn.zeroChild = ft.refOf(zc, leafRefs, len(ft.nodes)+len(queue)+1)
+			if !zc.isLeaf() {
+				queue = append(queue, zc)
+			}
+		} else {
+			fn.zeroChild = frozenNone
+		}
+
+		nr.forEachChild(func(ch byte, child *nodeRef) {
+			ft.childKeys = append(ft.childKeys, ch)
+			ft.childRefs = append(ft.childRefs, ft.refOf(child, leafRefs, len(ft.nodes)+len(queue)+1))
+
+			if !child.isLeaf() {
+				queue = append(queue, child)
+			}
+		})
+
+		fn.childCount = uint16(len(ft.childKeys) - int(fn.childStart)) //#nosec:G115
+		ft.nodes = append(ft.nodes, fn)
+	}
+
+	return ft
+}
+
+// freezeLeaves copies all leaves in key order and returns their references.
+// The keys are addressed by 32-bit offsets, so their total length is limited to 4GB.
+func (ft *frozenTree) freezeLeaves(tr *tree) map[*nodeRef]frozenRef {
+	leafRefs := make(map[*nodeRef]frozenRef, tr.size)
+	leaves := make([]*nodeRef, 0, tr.size)
+	keysLen := 0
+
+	tr.ForEach(func(node Node) bool {
+		nr, _ := node.(*nodeRef)
+		leaves = append(leaves, nr)
+		keysLen += len(nr.leaf().key)
+
+		return true
+	})
+
+	if uint64(keysLen) > math.MaxUint32 {
+		panic("art: the total length of the keys exceeds the frozen tree limit of 4GB")
+	}
+
+	ft.keys = make([]byte, 0, keysLen)
+	ft.leaves = make([]frozenLeaf, len(leaves))
+	ft.values = make([]Value, len(leaves))
+
+	for i, nr := range leaves {
+		l := nr.leaf()
+		ft.leaves[i] = frozenLeaf{
+			keyStart: uint32(len(ft.keys)), //#nosec:G115
+			keyLen:   uint32(len(l.key)),   //#nosec:G115
+		}
+		ft.values[i] = l.value
+		ft.keys = append(ft.keys, l.key...)
+		leafRefs[nr] = ^frozenRef(i)
+	}
+
+	return leafRefs
+}
+
+// refOf returns the reference for the node. Inner nodes get the given index.
+func (ft *frozenTree) refOf(nr *nodeRef, leafRefs map[*nodeRef]frozenRef, idx int) frozenRef {
+	if nr.isLeaf() {
+		return leafRefs[nr]
+	}
+
+	return frozenRef(idx) //#nosec:G115
+}
+
+// findChild returns the child of the node for the given key character.
+func (ft *frozenTree) findChild(n *frozenNode, kc keyChar) frozenRef {
+	if kc.invalid {
+		return n.zeroChild
+	}
+
+	keys := ft.childKeys[n.childStart : n.childStart+uint32(n.childCount)]
+
+	// binary search in the sorted keys.
+	lo, hi := 0, len(keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if keys[mid] < kc.ch {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	if lo < len(keys) && keys[lo] == kc.ch {
+		return ft.childRefs[int(n.childStart)+lo]
+	}
+
+	return frozenNone
+}
+
+// childAt returns the child in the given slot of the node.
+// Slot 0 is the zero byte child, the other slots hold children in ascending key order.
+func (ft *frozenTree) childAt(n *frozenNode, slot int) frozenRef {
+	if slot == 0 {
+		return n.zeroChild
+	}
+
+	return ft.childRefs[int(n.childStart)+slot-1]
+}
+
+// nodeOf returns the public Node for the reference.
+func (ft *frozenTree) nodeOf(ref frozenRef) Node {
+	if ref.isLeaf() {
+		return frozenLeafNode{tree: ft, idx: ref.leafIdx()}
+	}
+
+	return &ft.nodes[ref]
+}
+
+// Search searches for the given key in the tree.
+func (ft *frozenTree) Search(key Key) (Value, bool) {
+	keyOffset := 0
+
+	ref := ft.root
+	for ref != frozenNone {
+		if ref.isLeaf() {
+			if idx := ref.leafIdx(); bytes.Equal(ft.key(idx), key) {
+				return ft.values[idx], true
+			}
+
+			return nil, false
+		}
+
+		n := &ft.nodes[ref]
+		if n.prefixLen > 0 {
+			prefixLen := minInt(int(n.prefixLen), maxPrefixLen)
+			if len(key)-keyOffset < prefixLen || !bytes.Equal(n.prefix[:prefixLen], key[keyOffset:keyOffset+prefixLen]) {
+				return nil, false
+			}
+
+			keyOffset += int(n.prefixLen)
+		}
+
+		ref = ft.findChild(n, key.charAt(keyOffset))
+		keyOffset++
+	}
+
+	return nil, false
+}
+
+// ForEach iterates over all nodes in the tree and calls the callback function.
+func (ft *frozenTree) ForEach(callback Callback, opts ...int) {
+	options := traverseOptions(opts...)
+
+	// leaves are stored in key order, so there is no need to walk the nodes.
+	if !options.hasNode() && !options.hasBreadthFirst() {
+		ft.forEachLeaf(0, len(ft.leaves), callback, options.hasReverse())
+
+		return
+	}
+
+	forEachNext(ft.Iterator(opts...), callback)
+}
+
+// forEachLeaf calls the callback for the leaves in [from, to).
+func (ft *frozenTree) forEachLeaf(from, to int, callback Callback, reverse bool) {
+	if reverse {
+		for i := to - 1; i >= from; i-- {
+			if !callback(frozenLeafNode{tree: ft, idx: i}) {
+				return
+			}
+		}
+
+		return
+	}
+
+	for i := from; i < to; i++ {
+		if !callback(frozenLeafNode{tree: ft, idx: i}) {
+			return
+		}
+	}
+}
+
+// ForEachPrefix iterates over all leaves with the given key prefix.
+func (ft *frozenTree) ForEachPrefix(key Key, callback Callback, opts ...int) {
+	options := traverseOptions(opts...)
+
+	// leaves with the same prefix are adjacent.
+	from := sort.Search(len(ft.leaves), func(i int) bool {
+		return bytes.Compare(ft.key(i), key) >= 0
+	})
+
+	to := from
+	for to < len(ft.leaves) && bytes.HasPrefix(ft.key(to), key) {
+		to++
+	}
+
+	ft.forEachLeaf(from, to, callback, options.hasReverse())
+}
+
+// Iterator returns a new tree iterator.
+func (ft *frozenTree) Iterator(opts ...int) Iterator {
+	options := traverseOptions(opts...)
+	if options.hasBreadthFirst() {
+		return newFrozenBreadthFirstIterator(ft, options)
+	}
+
+	it := &frozenIterator{
+		tree:    ft,
+		opts:    options,
+		pending: ft.root,
+	}
+
+	it.advance()
+
+	return it
+}
+
+// Minimum returns the minimum key in the tree.
+func (ft *frozenTree) Minimum() (Value, bool) {
+	if len(ft.leaves) == 0 {
+		return nil, false
+	}
+
+	return ft.values[0], true
+}
+
+// Maximum returns the maximum key in the tree.
+func (ft *frozenTree) Maximum() (Value, bool) {
+	if len(ft.leaves) == 0 {
+		return nil, false
+	}
+
+	return ft.values[len(ft.values)-1], true
+}
+
+// Size returns the number of elements in the tree.
+func (ft *frozenTree) Size() int {
+	return len(ft.leaves)
+}
+
+// Thaw converts the frozen tree back into a mutable tree.
+func (ft *frozenTree) Thaw() Tree {
+	tr := newTree()
+	for i := range ft.leaves {
+		tr.Insert(ft.key(i), ft.values[i])
+	}
+
+	return tr
+}
+
+// frozenFrame is the iteration state of one inner node.
+type frozenFrame struct {
+	node int
+	slot int // number of visited child slots
+}
+
+// frozenIterator iterates over the frozen tree nodes in pre-order.
+type frozenIterator struct {
+	tree     *frozenTree
+	opts     traverseOpts
+	stack    []frozenFrame
+	pending  frozenRef // the root node, until it is visited
+	nextNode Node
+}
+
+// HasNext returns true if there are more nodes to iterate.
+func (it *frozenIterator) HasNext() bool {
+	return it.nextNode != nil
+}
+
+// Next returns the next node.
+// It returns ErrNoMoreNodes if there are no more nodes to iterate.
+func (it *frozenIterator) Next() (Node, error) {
+	if !it.HasNext() {
+		return nil, ErrNoMoreNodes
+	}
+
+	current := it.nextNode
+	it.advance()
+
+	return current, nil
+}
+
+// Remove returns ErrReadOnlyTree, the frozen tree cannot be modified.
+func (it *frozenIterator) Remove() error {
+	return ErrReadOnlyTree
+}
+
+// advance moves the iterator to the next node that matches the options.
+func (it *frozenIterator) advance() {
+	for {
+		ref := it.nextRef()
+		if ref == frozenNone {
+			it.nextNode = nil
+
+			return
+		}
+
+		if ref.isLeaf() && it.opts.hasLeaf() || !ref.isLeaf() && it.opts.hasNode() {
+			it.nextNode = it.tree.nodeOf(ref)
+
+			return
+		}
+	}
+}
+
+// nextRef returns the next node in pre-order.
+func (it *frozenIterator) nextRef() frozenRef {
+	if ref := it.pending; ref != frozenNone {
+		it.pending = frozenNone
+		it.push(ref)
+
+		return ref
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		n := &it.tree.nodes[top.node]
+
+		if top.slot > int(n.childCount) {
+			it.stack = it.stack[:len(it.stack)-1]
+
+			continue
+		}
+
+		slot := ternary(it.opts.hasReverse(), int(n.childCount)-top.slot, top.slot)
+		top.slot++
+
+		if ref := it.tree.childAt(n, slot); ref != frozenNone {
+			it.push(ref)
+
+			return ref
+		}
+	}
+
+	return frozenNone
+}
+
+// push adds an inner node to the iteration stack.
+func (it *frozenIterator) push(ref frozenRef) {
+	if !ref.isLeaf() {
+		it.stack = append(it.stack, frozenFrame{node: int(ref)})
+	}
+}
@@ -0,0 +1,209 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectNodes returns kinds and keys of the nodes visited by ForEach.
+func collectNodes(tr interface{ ForEach(Callback, ...int) }, opts ...int) ([]Kind, []string) {
+	var (
+		kinds []Kind
+		keys  []string
+	)
+
+	tr.ForEach(func(node Node) bool {
+		kinds = append(kinds, node.Kind())
+		keys = append(keys, string(node.Key()))
+
+		return true
+	}, opts...)
+
+	return kinds, keys
+}
+
+func TestFrozenTreeWords(t *testing.T) {
+	t.Parallel()
+
+	tree, words := treeWithData("test/assets/words.txt")
+	frozen := Freeze(tree)
+
+	assert.Equal(t, tree.Size(), frozen.Size())
+
+	for _, w := range words {
+		val, found := frozen.Search(w)
+		require.True(t, found, string(w))
+		assert.Equal(t, w, val)
+	}
+
+	for _, w := range []string{"", "zzzzzz", "Aa!", "abandonment!"} {
+		_, found := frozen.Search(Key(w))
+		assert.False(t, found, w)
+	}
+
+	minVal, _ := tree.Minimum()
+	frozenMin, found := frozen.Minimum()
+	assert.True(t, found)
+	assert.Equal(t, minVal, frozenMin)
+
+	maxVal, _ := tree.Maximum()
+	frozenMax, found := frozen.Maximum()
+	assert.True(t, found)
+	assert.Equal(t, maxVal, frozenMax)
+
+	for _, opts := range [][]int{
+		{TraverseLeaf},
+		{TraverseNode},
+		{TraverseAll},
+		{TraverseLeaf, TraverseReverse},
+		{TraverseAll, TraverseReverse},
+	} {
+		expectedKinds, expectedKeys := collectNodes(tree, opts...)
+		kinds, keys := collectNodes(frozen, opts...)
+		assert.Equal(t, expectedKinds, kinds, opts)
+		assert.Equal(t, expectedKeys, keys, opts)
+	}
+
+	stats := collectStats(frozen.Iterator(TraverseAll))
+	assert.Equal(t, treeStats{235886, 113419, 10433, 403, 1}, stats)
+}
+
+func TestFrozenTreePrefix(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := treeWithData("test/assets/words.txt")
+	frozen := Freeze(tree)
+
+	for _, prefix := range []Key{Key("a"), Key("antisa"), Key("Z"), Key("zz"), Key("nonexistent"), Key{}, nil} {
+		for _, reverse := range []int{0, TraverseReverse} {
+			var expected, actual []string
+
+			tree.ForEachPrefix(prefix, func(node Node) bool {
+				expected = append(expected, string(node.Key()))
+
+				return true
+			}, reverse)
+
+			frozen.ForEachPrefix(prefix, func(node Node) bool {
+				actual = append(actual, string(node.Key()))
+
+				return true
+			}, reverse)
+
+			assert.Equal(t, expected, actual, string(prefix))
+		}
+	}
+}
+
+func TestFrozenTreeDetachedAndThaw(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for _, key := range []string{"a", "ab", "abc", "b"} {
+		tree.Insert(Key(key), key)
+	}
+
+	frozen := Freeze(tree)
+
+	tree.Insert(Key("c"), "c")
+	tree.Delete(Key("a"))
+
+	assert.Equal(t, 4, frozen.Size())
+
+	_, found := frozen.Search(Key("c"))
+	assert.False(t, found)
+
+	val, found := frozen.Search(Key("a"))
+	assert.True(t, found)
+	assert.Equal(t, "a", val)
+
+	thawed := frozen.Thaw()
+	assert.Equal(t, 4, thawed.Size())
+
+	thawed.Insert(Key("d"), "d")
+	assert.Equal(t, 5, thawed.Size())
+	assert.Equal(t, 4, frozen.Size())
+
+	_, keys := collectNodes(thawed)
+	assert.Equal(t, []string{"a", "ab", "abc", "b", "d"}, keys)
+}
+
+func TestFrozenTreeEmptyAndSingleLeaf(t *testing.T) {
+	t.Parallel()
+
+	frozen := Freeze(New())
+	assert.Equal(t, 0, frozen.Size())
+
+	_, found := frozen.Search(Key("a"))
+	assert.False(t, found)
+
+	_, found = frozen.Minimum()
+	assert.False(t, found)
+
+	_, found = frozen.Maximum()
+	assert.False(t, found)
+
+	it := frozen.Iterator(TraverseAll)
+	assert.False(t, it.HasNext())
+
+	_, err := it.Next()
+	assert.ErrorIs(t, err, ErrNoMoreNodes)
+
+	tree := New()
+	tree.Insert(Key("single"), 1)
+	frozen = Freeze(tree)
+
+	val, found := frozen.Search(Key("single"))
+	assert.True(t, found)
+	assert.Equal(t, 1, val)
+
+	kinds, _ := collectNodes(frozen, TraverseAll)
+	assert.Equal(t, []Kind{Leaf}, kinds)
+}
+
+func TestFrozenTreeZeroChildAndNode256(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("x"), "x")
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{'x', byte(i)}, i)
+	}
+
+	require.Equal(t, Node256, tree.root.kind)
+
+	frozen := Freeze(tree)
+
+	val, found := frozen.Search(Key("x"))
+	assert.True(t, found)
+	assert.Equal(t, "x", val)
+
+	for i := 0; i < 256; i++ {
+		val, found := frozen.Search(Key{'x', byte(i)})
+		assert.True(t, found)
+		assert.Equal(t, i, val)
+	}
+
+	expectedKinds, expectedKeys := collectNodes(tree, TraverseAll, TraverseReverse)
+	kinds, keys := collectNodes(frozen, TraverseAll, TraverseReverse)
+	assert.Equal(t, expectedKinds, kinds)
+	assert.Equal(t, expectedKeys, keys)
+}
+
+func TestFreezeCustomTree(t *testing.T) {
+	t.Parallel()
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{treeWithKeys("a", "ab", "b")}
+	frozen := Freeze(wrapped)
+
+	_, keys := collectNodes(frozen)
+	assert.Equal(t, []string{"a", "ab", "b"}, keys)
+
+	val, found := frozen.Search(Key("ab"))
+	assert.True(t, found)
+	assert.Equal(t, "ab", val)
+}
@@ -0,0 +1,68 @@
+package art
+
+// Hooks contains optional callbacks for the structural events of the tree, see WithHooks.
+// The callbacks are called synchronously by Insert and Delete, so they must be fast
+// and must not modify the tree. A nil callback is not called.
+type Hooks struct {
+	// OnGrow is called when an inner node runs out of capacity
+	// and is replaced by a bigger node, e.g. Node4 to Node16.
+	OnGrow func(from, to Kind)
+
+	// OnShrink is called when an inner node becomes under-utilized
+	// and is replaced by a smaller node, e.g. Node16 to Node4.
+	// A Node4 with a single child is replaced by the child,
+	// so to can be Leaf or any inner node kind.
+	OnShrink func(from, to Kind)
+
+	// OnSplitLeaf is called when a new key is inserted next to an existing leaf
+	// and both are moved under a new Node4 with the prefix of prefixLen bytes.
+	OnSplitLeaf func(prefixLen int)
+
+	// OnSplitNode is called when a new key does not match the prefix of an inner node.
+	// The node prefix is reassigned and the node is moved under a new Node4
+	// with the prefix of prefixLen bytes.
+	OnSplitNode func(prefixLen int)
+}
+
+// treeOptions contains options for NewWithOptions.
+type treeOptions struct {
+	hooks *Hooks
+}
+
+// TreeOption is a function that sets an option for NewWithOptions.
+type TreeOption func(opts *treeOptions)
+
+// WithHooks sets the callbacks for the structural events of the tree.
+func WithHooks(hooks *Hooks) TreeOption {
+	return func(opts *treeOptions) {
+		opts.hooks = hooks
+	}
+}
+
+// grow calls the OnGrow hook if it is set.
+func (h *Hooks) grow(from, to Kind) {
+	if h != nil && h.OnGrow != nil {
+		h.OnGrow(from, to)
+	}
+}
+
+// shrink calls the OnShrink hook if it is set.
+func (h *Hooks) shrink(from, to Kind) {
+	if h != nil && h.OnShrink != nil {
+		h.OnShrink(from, to)
+	}
+}
+
+// splitLeaf calls the OnSplitLeaf hook if it is set.
+func (h *Hooks) splitLeaf(prefixLen int) {
+	if h != nil && h.OnSplitLeaf != nil {
+		h.OnSplitLeaf(prefixLen)
+	}
+}
+
+// splitNode calls the OnSplitNode hook if it is set.
+func (h *Hooks) splitNode(prefixLen int) {
+	if h != nil && h.OnSplitNode != nil {
+		h.OnSplitNode(prefixLen)
+	}
+}
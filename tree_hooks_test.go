@@ -0,0 +1,106 @@
+package art
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHooks returns hooks that record all events.
+func recordingHooks(events *[]string) *Hooks {
+	return &Hooks{
+		OnGrow: func(from, to Kind) {
+			*events = append(*events, fmt.Sprintf("grow %s->%s", from, to))
+		},
+		OnShrink: func(from, to Kind) {
+			*events = append(*events, fmt.Sprintf("shrink %s->%s", from, to))
+		},
+		OnSplitLeaf: func(prefixLen int) {
+			*events = append(*events, fmt.Sprintf("split leaf %d", prefixLen))
+		},
+		OnSplitNode: func(prefixLen int) {
+			*events = append(*events, fmt.Sprintf("split node %d", prefixLen))
+		},
+	}
+}
+
+func TestTreeHooksGrowShrink(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+
+	tree := NewWithOptions(WithHooks(recordingHooks(&events)))
+	tree.Insert(Key("x"), "x")
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{'x', byte(i)}, i)
+	}
+
+	assert.Equal(t, []string{
+		"split leaf 1",
+		"grow Node4->Node16",
+		"grow Node16->Node48",
+		"grow Node48->Node256",
+	}, events)
+
+	events = nil
+
+	for i := 255; i >= 0; i-- {
+		tree.Delete(Key{'x', byte(i)})
+	}
+
+	assert.Equal(t, []string{
+		"shrink Node256->Node48",
+		"shrink Node48->Node16",
+		"shrink Node16->Node4",
+		"shrink Node4->Leaf",
+	}, events)
+}
+
+func TestTreeHooksSplitNode(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+
+	tree := NewWithOptions(WithHooks(recordingHooks(&events)))
+	tree.Insert(Key("abcd"), 1)
+	tree.Insert(Key("abce"), 2)
+	tree.Insert(Key("abx"), 3)
+	tree.Insert(Key("abcd"), 4) // update, no structural change
+
+	assert.Equal(t, []string{"split leaf 3", "split node 2"}, events)
+}
+
+func TestTreeHooksPartial(t *testing.T) {
+	t.Parallel()
+
+	grows := 0
+	tree := NewWithOptions(WithHooks(&Hooks{OnGrow: func(Kind, Kind) { grows++ }}))
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{byte(i)}, i)
+	}
+
+	for i := 0; i < 256; i++ {
+		tree.Delete(Key{byte(i)})
+	}
+
+	assert.Equal(t, 3, grows)
+	assert.Equal(t, 0, tree.Size())
+}
+
+func TestTreeHooksNil(t *testing.T) {
+	t.Parallel()
+
+	tree := NewWithOptions(WithHooks(nil))
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{'a', byte(i)}, i)
+	}
+
+	for i := 0; i < 256; i++ {
+		tree.Delete(Key{'a', byte(i)})
+	}
+
+	assert.Equal(t, 0, tree.Size())
+}
@@ -56,6 +56,7 @@ func (tr *tree) splitLeaf(nrpCurLeaf **nodeRef, key Key, value Value, keyOffset
 
 	// replace the old leaf with the new node4
 	replaceRef(nrpCurLeaf, nr4)
+	tr.hooks.splitLeaf(keysLCP)
 
 	return nil, treeOpInserted
 }
@@ -86,13 +87,14 @@ func (tr *tree) splitNode(nrp **nodeRef, key Key, value Value, keyOffset int, mi
 	tr.reassignPrefix(nr4, nr, key, value, keyOffset, mismatchIdx)
 
 	replaceRef(nrp, nr4)
+	tr.hooks.splitNode(mismatchIdx)
 
 	return nil, treeOpInserted
 }
 
 func (tr *tree) reassignPrefix(newNRP *nodeRef, curNRP *nodeRef, key Key, value Value, keyOffset int, mismatchIdx int) {
 	curNode := curNRP.node()
-	curNode.prefixLen -= uint16(mismatchIdx + 1) //#nosec:G115
+	curNode.prefixLen -= uint32(mismatchIdx + 1) //#nosec:G115
 
 	idx := keyOffset + mismatchIdx
 
@@ -118,7 +120,12 @@ func (tr *tree) continueInsertion(nrp **nodeRef, key Key, value Value, keyOffset
 	}
 
 	// No child found, create a new leaf node
+	kind := nr.kind
 	nr.addChild(key.charAt(keyOffset), factory.newLeaf(key, value))
 
+	if nr.kind != kind {
+		tr.hooks.grow(kind, nr.kind)
+	}
+
 	return nil, treeOpInserted
 }
@@ -2,23 +2,63 @@ package art
 
 import "errors"
 
+// iteratorStackSize is the initial capacity of the iteration state,
+// it covers the depth of most trees, so the iteration does not allocate.
+const iteratorStackSize = 32
+
 // state represents the iteration state during tree traversal.
 type state struct {
-	items []*iteratorContext
+	items []iteratorContext
+}
+
+// newState creates a new iteration state with the pre-sized stack.
+func newState() *state {
+	return &state{items: make([]iteratorContext, 0, iteratorStackSize)}
 }
 
 // push adds a new iterator context to the state.
-func (s *state) push(ctx *iteratorContext) {
+func (s *state) push(ctx iteratorContext) {
 	s.items = append(s.items, ctx)
 }
 
 // current returns the current iterator context and a flag indicating if there is any.
+// The context is valid until the next push.
 func (s *state) current() (*iteratorContext, bool) {
 	if len(s.items) == 0 {
 		return nil, false
 	}
 
-	return s.items[len(s.items)-1], true
+	return &s.items[len(s.items)-1], true
+}
+
+// repair fixes the iteration state after a leaf has been deleted from the tree.
+// The state is the path to the next node, so every context points to the node of the following one.
+// The deletion changes only the parent of the leaf: the children of the parent can move
+// when it loses the child or shrinks, or the parent collapses into its only remaining child.
+// The nodes are replaced in place, so only the position of the parent context is fixed.
+func (s *state) repair(nextNode **nodeRef) {
+	for i := len(s.items) - 2; i >= 0; i-- {
+		frame, child := &s.items[i].frame, s.items[i+1].frame.nr
+		if frame.ord <= frame.nr.maxChildOrdinal() && frame.nr.childAtOrdinal(frame.ord) == child {
+			continue
+		}
+
+		if frame.nr.ref != child.ref {
+			frame.ord = frame.nr.ordinalOfChild(child)
+
+			return
+		}
+
+		// the parent has collapsed into the child, which is now referenced by the parent.
+		s.items[i+1].frame.nr = frame.nr
+		if *nextNode == child {
+			*nextNode = frame.nr
+		}
+
+		s.items = append(s.items[:i], s.items[i+1:]...)
+
+		return
+	}
 }
 
 // discard removes the last iterator context from the state.
@@ -32,32 +72,23 @@ func (s *state) discard() {
 
 // iteratorContext represents the context of the tree iterator for one node.
 type iteratorContext struct {
-	nextChildFn traverseFunc
-	children    []*nodeRef
+	frame   traverseFrame
+	reverse bool
 }
 
 // newIteratorContext creates a new iterator context for the given node.
-func newIteratorContext(nr *nodeRef, reverse bool) *iteratorContext {
-	return &iteratorContext{
-		nextChildFn: newTraverseFunc(nr, reverse),
-		children:    toNode(nr).allChildren(),
+func newIteratorContext(nr *nodeRef, reverse bool) iteratorContext {
+	return iteratorContext{
+		frame:   newTraverseFrame(nr, !reverse),
+		reverse: reverse,
 	}
 }
 
 // next returns the next node reference and a flag indicating if there are more nodes.
 func (ic *iteratorContext) next() (*nodeRef, bool) {
-	for {
-		idx, ok := ic.nextChildFn()
-		if !ok {
-			break
-		}
-
-		if child := ic.children[idx]; child != nil {
-			return child, true
-		}
-	}
+	child := ic.frame.step(!ic.reverse)
 
-	return nil, false
+	return child, child != nil
 }
 
 // iterator is a struct for tree traversal iteration.
@@ -66,6 +97,7 @@ type iterator struct {
 	tree     *tree    // tree to iterate
 	state    *state   // iteration state
 	nextNode *nodeRef // next node to iterate
+	last     *nodeRef // last returned node
 	reverse  bool     // indicates if the iteration is in reverse order
 }
 
@@ -74,8 +106,10 @@ var _ Iterator = (*iterator)(nil)
 
 // newTreeIterator creates a new tree iterator.
 func newTreeIterator(tr *tree, opts traverseOpts) Iterator {
-	state := &state{}
-	state.push(newIteratorContext(tr.root, opts.hasReverse()))
+	state := newState()
+	if tr.root != nil {
+		state.push(newIteratorContext(tr.root, opts.hasReverse()))
+	}
 
 	it := &iterator{
 		version:  tr.version,
@@ -89,6 +123,11 @@ func newTreeIterator(tr *tree, opts traverseOpts) Iterator {
 		return it
 	}
 
+	return newBufferedIterator(it, opts)
+}
+
+// newBufferedIterator creates a new iterator over the nodes of it matching the options.
+func newBufferedIterator(it *iterator, opts traverseOpts) *bufferedIterator {
 	bit := &bufferedIterator{
 		opts: opts,
 		it:   it,
@@ -123,11 +162,36 @@ func (it *iterator) Next() (Node, error) {
 	}
 
 	current := it.nextNode
+	it.last = current
 	it.next()
 
 	return current, nil
 }
 
+// Remove deletes the leaf returned by the last call to Next from the tree.
+func (it *iterator) Remove() error {
+	if it.hasConcurrentModification() {
+		return ErrConcurrentModification
+	}
+
+	if it.last == nil || !it.last.isLeaf() {
+		return ErrNoLeafToRemove
+	}
+
+	it.removeKey(it.last.leaf().key)
+	it.last = nil
+
+	return nil
+}
+
+// removeKey deletes the key of an already visited leaf from the tree
+// and repairs the iteration state in place, so the iteration continues with the same next node.
+func (it *iterator) removeKey(key Key) {
+	it.tree.Delete(key)
+	it.version = it.tree.version
+	it.state.repair(&it.nextNode)
+}
+
 // next moves the iterator to the next node.
 func (it *iterator) next() {
 	for {
@@ -154,9 +218,10 @@ func (it *iterator) next() {
 // It allows to iterate over leaf or non-leaf nodes only.
 type bufferedIterator struct {
 	opts     traverseOpts
-	it       Iterator
+	it       *iterator
 	nextNode Node
 	nextErr  error
+	last     Node
 }
 
 // HasNext returns true if there are more nodes to iterate.
@@ -182,9 +247,27 @@ func (bit *bufferedIterator) Next() (Node, error) {
 		return nil, bit.nextErr
 	}
 
+	bit.last = current
+
 	return current, nil
 }
 
+// Remove deletes the leaf returned by the last call to Next from the tree.
+func (bit *bufferedIterator) Remove() error {
+	if bit.it.hasConcurrentModification() {
+		return ErrConcurrentModification
+	}
+
+	if bit.last == nil || bit.last.Kind() != Leaf {
+		return ErrNoLeafToRemove
+	}
+
+	bit.it.removeKey(bit.last.Key())
+	bit.last = nil
+
+	return nil
+}
+
 // hasLeafIterator checks if the iterator is for leaf nodes.
 func (bit *bufferedIterator) hasLeafIterator() bool {
 	return bit.opts&TraverseLeaf == TraverseLeaf
@@ -0,0 +1,186 @@
+package art
+
+import "bytes"
+
+// resumableIterator is a leaf iterator that survives modifications of the tree.
+// It remembers the last returned key and re-seeks the tree after the tree version changes.
+type resumableIterator struct {
+	version   int               // tree version at the time of the last seek
+	tree      *tree             // tree to iterate
+	it        *bufferedIterator // leaf iterator positioned after the last returned key
+	lastKey   Key               // last returned key, nil before the first one
+	canRemove bool              // indicates if the last returned key can be removed
+	reverse   bool              // indicates if the iteration is in reverse order
+}
+
+// assert that resumableIterator implements the Iterator interface.
+var _ Iterator = (*resumableIterator)(nil)
+
+// newResumableIterator creates a new resumable leaf iterator.
+func newResumableIterator(tr *tree, reverse bool) *resumableIterator {
+	it := &resumableIterator{
+		tree:    tr,
+		reverse: reverse,
+	}
+
+	it.seek()
+
+	return it
+}
+
+// HasNext returns true if there are more leaves to iterate.
+func (it *resumableIterator) HasNext() bool {
+	it.sync()
+
+	return it.it.HasNext()
+}
+
+// Next returns the next leaf.
+// It returns ErrNoMoreNodes if there are no more leaves to iterate.
+func (it *resumableIterator) Next() (Node, error) {
+	it.sync()
+
+	node, err := it.it.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	it.lastKey = node.Key()
+	it.canRemove = true
+
+	return node, nil
+}
+
+// Remove deletes the leaf returned by the last call to Next from the tree.
+// The iterator re-seeks the tree on the next call.
+func (it *resumableIterator) Remove() error {
+	if !it.canRemove {
+		return ErrNoLeafToRemove
+	}
+
+	it.canRemove = false
+	it.tree.Delete(it.lastKey)
+
+	return nil
+}
+
+// sync re-seeks the tree if it has been modified since the last seek.
+func (it *resumableIterator) sync() {
+	if it.version != it.tree.version {
+		it.seek()
+	}
+}
+
+// seek positions the iterator after the last returned key.
+func (it *resumableIterator) seek() {
+	it.version = it.tree.version
+	it.it = newBufferedIterator(it.tree.seekAfter(it.lastKey, it.reverse), TraverseLeaf)
+}
+
+// seekAfter creates an iterator over the nodes in pre-order that starts with the first node
+// whose keys are all after the key, i.e. greater than the key, or less than the key in reverse order.
+// A nil key means that all nodes are after it.
+func (tr *tree) seekAfter(key Key, reverse bool) *iterator {
+	it := &iterator{
+		version: tr.version,
+		tree:    tr,
+		state:   newState(),
+		reverse: reverse,
+	}
+
+	if tr.root == nil {
+		return it
+	}
+
+	if key == nil {
+		it.nextNode = tr.root
+		it.state.push(newIteratorContext(tr.root, reverse))
+
+		return it
+	}
+
+	// first and last return the first and the last key of the subtree in the iteration order.
+	first, last := (*nodeRef).minimum, (*nodeRef).maximum
+	if reverse {
+		first, last = last, first
+	}
+
+	// after returns true if the key a is after the key b in the iteration order.
+	after := func(a, b Key) bool {
+		return ternary(reverse, bytes.Compare(a, b) < 0, bytes.Compare(a, b) > 0)
+	}
+
+	keyOffset := 0
+
+	for nr := tr.root; ; {
+		if !after(last(nr).key, key) {
+			it.next() // the whole subtree is behind the key, continue with the next sibling
+
+			return it
+		}
+
+		it.state.push(newIteratorContext(nr, reverse))
+
+		if after(first(nr).key, key) {
+			it.nextNode = nr
+
+			return it
+		}
+
+		// the subtree contains the keys on both sides of the key,
+		// so the node path is a prefix of the key.
+		keyOffset += int(nr.node().prefixLen)
+		child := nr.seekChild(key, keyOffset, reverse)
+
+		ctx, _ := it.state.current()
+		for next, ok := ctx.next(); ok && next != child; next, ok = ctx.next() {
+			// skip the children behind the key
+		}
+
+		if child == nil {
+			it.next() // all children are behind the key
+
+			return it
+		}
+
+		nr = child
+		keyOffset++
+	}
+}
+
+// seekChild returns the first child in the iteration order that is not entirely behind the key.
+// The node path must be a prefix of the key, the children are selected by the key byte at keyOffset.
+func (nr *nodeRef) seekChild(key Key, keyOffset int, reverse bool) *nodeRef {
+	var found *nodeRef
+
+	if keyOffset >= len(key) {
+		// the zero child is the key itself, the other children are greater than the key.
+		if !reverse {
+			nr.rangeChildren(false, func(_ byte, child *nodeRef) bool {
+				found = child
+
+				return false
+			})
+		}
+
+		return found
+	}
+
+	ch := key[keyOffset]
+	nr.rangeChildren(reverse, func(childCh byte, child *nodeRef) bool {
+		if ternary(reverse, childCh <= ch, childCh >= ch) {
+			found = child
+
+			return false
+		}
+
+		return true
+	})
+
+	if found == nil && reverse {
+		// the zero child is a prefix of the key, so it is less than the key.
+		return nr.zeroChild()
+	}
+
+	return found
+}
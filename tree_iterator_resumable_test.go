@@ -0,0 +1,171 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextKey returns the key of the next node.
+func nextKey(t *testing.T, it Iterator) string {
+	t.Helper()
+
+	require.True(t, it.HasNext())
+
+	node, err := it.Next()
+	require.NoError(t, err)
+	require.Equal(t, Leaf, node.Kind())
+
+	return string(node.Key())
+}
+
+// remainingKeys returns the keys of the remaining nodes.
+func remainingKeys(t *testing.T, it Iterator) []string {
+	t.Helper()
+
+	var keys []string
+	for it.HasNext() {
+		keys = append(keys, nextKey(t, it))
+	}
+
+	node, err := it.Next()
+	assert.Nil(t, node)
+	assert.Equal(t, ErrNoMoreNodes, err)
+
+	return keys
+}
+
+func TestResumableIteratorInsertDelete(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("b", "d", "f", "h", "j", "l", "n", "p", "r", "t")
+	it := tree.Iterator(TraverseResumable)
+
+	assert.Equal(t, "b", nextKey(t, it))
+	assert.Equal(t, "d", nextKey(t, it))
+
+	tree.Insert(Key("a"), "a")   // behind the iterator
+	tree.Insert(Key("e"), "e")   // ahead of the iterator
+	tree.Insert(Key("dd"), "dd") // ahead, under the last returned key
+	tree.Delete(Key("d"))        // the last returned key
+	tree.Delete(Key("f"))        // ahead of the iterator
+
+	assert.Equal(t, "dd", nextKey(t, it))
+	assert.Equal(t, "e", nextKey(t, it))
+
+	tree.Delete(Key("h"))
+	assert.Equal(t, []string{"j", "l", "n", "p", "r", "t"}, remainingKeys(t, it))
+
+	tree.Insert(Key("z"), "z")
+	assert.Equal(t, []string{"z"}, remainingKeys(t, it))
+}
+
+func TestResumableIteratorReverse(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("b", "d", "f", "h", "j", "l", "n", "p", "r", "t")
+	it := tree.Iterator(TraverseResumable | TraverseReverse)
+
+	assert.Equal(t, "t", nextKey(t, it))
+
+	tree.Insert(Key("u"), "u")   // behind the iterator
+	tree.Insert(Key("rr"), "rr") // ahead of the iterator
+	tree.Insert(Key("tt"), "tt") // behind the iterator
+	tree.Delete(Key("p"))
+
+	assert.Equal(t, []string{"rr", "r", "n", "l", "j", "h", "f", "d", "b"}, remainingKeys(t, it))
+}
+
+func TestResumableIteratorIgnoresNodes(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("b", "d", "f", "h", "j", "l", "n", "p", "r", "t")
+	tree.Insert(Key("bb"), "bb")
+
+	keys := remainingKeys(t, tree.Iterator(TraverseResumable|TraverseAll|TraverseBreadthFirst))
+	assert.Equal(t, []string{"b", "bb", "d", "f", "h", "j", "l", "n", "p", "r", "t"}, keys)
+}
+
+func TestResumableIteratorEmpty(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	it := tree.Iterator(TraverseResumable)
+	assert.False(t, it.HasNext())
+
+	tree.Insert(Key("a"), "a")
+	assert.Equal(t, []string{"a"}, remainingKeys(t, it))
+
+	tree.Delete(Key("a"))
+	assert.False(t, it.HasNext())
+}
+
+func TestResumableIteratorEmptyKey(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("b", "d", "f", "h", "j", "l", "n", "p", "r", "t")
+	tree.Insert(Key(""), "")
+
+	it := tree.Iterator(TraverseResumable)
+	assert.Equal(t, "", nextKey(t, it))
+
+	tree.Insert(Key("a"), "a")
+	assert.Equal(t, "a", nextKey(t, it))
+}
+
+func TestResumableForEachDeleteWords(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := treeWithData("test/assets/words.txt")
+
+	var sorted []Key
+
+	tree.ForEach(func(node Node) bool {
+		sorted = append(sorted, node.Key())
+
+		return true
+	})
+
+	pos := 0
+
+	tree.ForEach(func(node Node) bool {
+		require.Equal(t, sorted[pos], node.Key())
+
+		// delete the current key and the key after it.
+		_, deleted := tree.Delete(node.Key())
+		require.True(t, deleted)
+
+		if pos+1 < len(sorted) {
+			tree.Delete(sorted[pos+1])
+		}
+
+		pos += 2
+
+		return true
+	}, TraverseResumable)
+
+	assert.GreaterOrEqual(t, pos, len(sorted))
+	assert.Equal(t, 0, tree.Size())
+	require.NoError(t, Verify(tree))
+}
+
+func TestResumableForEachInsertBehind(t *testing.T) {
+	t.Parallel()
+
+	tree := treeWithKeys("b", "d", "f", "h", "j", "l", "n", "p", "r", "t")
+
+	var keys []string
+
+	tree.ForEach(func(node Node) bool {
+		keys = append(keys, string(node.Key()))
+
+		// the key behind the iterator is not visited.
+		tree.Insert(append(Key{node.Key()[0] - 1}, 'x'), "x")
+
+		return true
+	}, TraverseResumable)
+
+	assert.Equal(t, []string{"b", "d", "f", "h", "j", "l", "n", "p", "r", "t"}, keys)
+	assert.Equal(t, 20, tree.Size())
+}
@@ -0,0 +1,330 @@
+package art
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// snapshotMagic identifies the binary snapshot format.
+// The last byte is the format version.
+var snapshotMagic = [4]byte{'A', 'R', 'T', 1} //nolint:gochecknoglobals
+
+// snapshotEntry is a single key/value pair of the binary snapshot.
+type snapshotEntry struct {
+	Key   []byte
+	Value interface{}
+}
+
+// make sure that tree implements the standard marshaling interfaces.
+var (
+	_ encoding.BinaryMarshaler   = (*tree)(nil)
+	_ encoding.BinaryUnmarshaler = (*tree)(nil)
+	_ gob.GobEncoder             = (*tree)(nil)
+	_ gob.GobDecoder             = (*tree)(nil)
+	_ json.Marshaler             = (*tree)(nil)
+	_ json.Unmarshaler           = (*tree)(nil)
+)
+
+// RegisterGob registers the tree implementation with encoding/gob,
+// so the tree can be gob-encoded as the Tree interface value, e.g. as a field of a struct.
+// It must be called before such values are encoded or decoded.
+// A tree encoded as the concrete value does not need the registration.
+func RegisterGob() {
+	gob.Register(newTree())
+}
+
+// MarshalBinary encodes the tree into the binary snapshot format, see MarshalTree.
+func (tr *tree) MarshalBinary() ([]byte, error) {
+	return MarshalTree(tr)
+}
+
+// UnmarshalBinary replaces the content of the tree with
+// the key/value pairs from the binary snapshot created by MarshalBinary.
+func (tr *tree) UnmarshalBinary(data []byte) error {
+	return UnmarshalTree(tr, data)
+}
+
+// GobEncode implements gob.GobEncoder, see MarshalBinary.
+func (tr *tree) GobEncode() ([]byte, error) {
+	return tr.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder, see UnmarshalBinary.
+func (tr *tree) GobDecode(data []byte) error {
+	return tr.UnmarshalBinary(data)
+}
+
+// MarshalJSON encodes the tree as a JSON object with keys in ascending order.
+// Keys are encoded as UTF-8 strings, see MarshalTreeJSON for details.
+func (tr *tree) MarshalJSON() ([]byte, error) {
+	return MarshalTreeJSON(tr)
+}
+
+// UnmarshalJSON replaces the content of the tree with the key/value pairs
+// of the JSON object created by MarshalJSON.
+func (tr *tree) UnmarshalJSON(data []byte) error {
+	return UnmarshalTreeJSON(tr, data)
+}
+
+// replaceWith replaces the content of the tree with the content of other tree.
+func (tr *tree) replaceWith(other *tree) {
+	tr.root = other.root
+	tr.size = other.size
+	tr.version++
+}
+
+// MarshalTree encodes the tree into the binary snapshot format.
+//
+// The snapshot contains all key/value pairs in ascending key order.
+// Values are encoded with encoding/gob, so custom value types must be
+// registered with gob.Register before the tree is encoded or decoded.
+func MarshalTree(t Tree) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.Write(snapshotMagic[:])
+
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(t.Size()); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	t.ForEach(func(node Node) bool {
+		err = enc.Encode(snapshotEntry{Key: node.Key(), Value: node.Value()})
+
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalTree replaces the content of the tree with
+// the key/value pairs from the binary snapshot created by MarshalTree.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func UnmarshalTree(t Tree, data []byte) error {
+	tr, ok := t.(*tree)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	if len(data) < len(snapshotMagic) || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic[:]) {
+		return ErrInvalidSnapshot
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(data[len(snapshotMagic):]))
+
+	var size int
+	if err := dec.Decode(&size); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err) //nolint:errorlint
+	}
+
+	loaded := newTree()
+
+	for i := 0; i < size; i++ {
+		var entry snapshotEntry
+		if err := dec.Decode(&entry); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err) //nolint:errorlint
+		}
+
+		loaded.Insert(entry.Key, entry.Value)
+	}
+
+	tr.replaceWith(loaded)
+
+	return nil
+}
+
+// JSONKeyEncoding defines how tree keys are represented in JSON object keys.
+type JSONKeyEncoding int
+
+// JSON key encodings.
+const (
+	// JSONKeyUTF8 encodes keys as UTF-8 strings.
+	// A backslash is encoded as `\\` and bytes that are not valid UTF-8 as `\xHH`,
+	// so arbitrary binary keys survive the round trip.
+	JSONKeyUTF8 JSONKeyEncoding = iota
+
+	// JSONKeyBase64 encodes keys with the standard base64 encoding.
+	JSONKeyBase64
+)
+
+// jsonOptions contains options for MarshalTreeJSON and UnmarshalTreeJSON.
+type jsonOptions struct {
+	keyEncoding JSONKeyEncoding
+}
+
+// JSONOption is a function that sets an option for MarshalTreeJSON and UnmarshalTreeJSON.
+type JSONOption func(opts *jsonOptions)
+
+// WithJSONKeyEncoding sets the encoding of the JSON object keys.
+func WithJSONKeyEncoding(encoding JSONKeyEncoding) JSONOption {
+	return func(opts *jsonOptions) {
+		opts.keyEncoding = encoding
+	}
+}
+
+func createJSONOptions(opts ...JSONOption) jsonOptions {
+	defOpts := jsonOptions{
+		keyEncoding: JSONKeyUTF8,
+	}
+
+	for _, opt := range opts {
+		opt(&defOpts)
+	}
+
+	return defOpts
+}
+
+// MarshalTreeJSON encodes the tree as a JSON object with keys in ascending order.
+// Values are encoded with encoding/json.
+func MarshalTreeJSON(t Tree, opts ...JSONOption) ([]byte, error) {
+	options := createJSONOptions(opts...)
+
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	buf.WriteByte('{')
+
+	t.ForEach(func(node Node) bool {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		var data []byte
+		if data, err = json.Marshal(encodeJSONKey(node.Key(), options.keyEncoding)); err != nil {
+			return false
+		}
+
+		buf.Write(data)
+		buf.WriteByte(':')
+
+		if data, err = json.Marshal(node.Value()); err != nil {
+			return false
+		}
+
+		buf.Write(data)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalTreeJSON replaces the content of the tree with the key/value pairs
+// of the JSON object created by MarshalTreeJSON with the same options.
+// Values are decoded with encoding/json into interface{} values,
+// e.g. numbers become float64.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func UnmarshalTreeJSON(t Tree, data []byte, opts ...JSONOption) error {
+	tr, ok := t.(*tree)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	options := createJSONOptions(opts...)
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	loaded := newTree()
+
+	for jsonKey, value := range object {
+		key, err := decodeJSONKey(jsonKey, options.keyEncoding)
+		if err != nil {
+			return err
+		}
+
+		loaded.Insert(key, value)
+	}
+
+	tr.replaceWith(loaded)
+
+	return nil
+}
+
+// encodeJSONKey converts the key to the JSON object key.
+func encodeJSONKey(key Key, encoding JSONKeyEncoding) string {
+	if encoding == JSONKeyBase64 {
+		return base64.StdEncoding.EncodeToString(key)
+	}
+
+	var buf bytes.Buffer
+
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRune(key[i:])
+
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			fmt.Fprintf(&buf, `\x%02x`, key[i])
+		case r == '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.Write(key[i : i+size])
+		}
+
+		i += size
+	}
+
+	return buf.String()
+}
+
+// decodeJSONKey converts the JSON object key back to the key.
+func decodeJSONKey(s string, encoding JSONKeyEncoding) (Key, error) {
+	if encoding == JSONKeyBase64 {
+		key, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err) //nolint:errorlint
+		}
+
+		return key, nil
+	}
+
+	key := make(Key, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			key = append(key, s[i])
+
+			continue
+		}
+
+		switch {
+		case i+1 < len(s) && s[i+1] == '\\':
+			key = append(key, '\\')
+			i++
+		case i+3 < len(s) && s[i+1] == 'x':
+			b, err := strconv.ParseUint(s[i+2:i+4], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid key escape %q", ErrInvalidSnapshot, s[i:i+4])
+			}
+
+			key = append(key, byte(b))
+			i += 3
+		default:
+			return nil, fmt.Errorf("%w: invalid key escape in %q", ErrInvalidSnapshot, s)
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,196 @@
+package art
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeMarshalBinaryWords(t *testing.T) {
+	t.Parallel()
+
+	tree, words := treeWithData("test/assets/words.txt")
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded := newTree()
+	require.NoError(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, len(words), loaded.Size())
+
+	for _, w := range words {
+		val, found := loaded.Search(w)
+		assert.True(t, found)
+		assert.Equal(t, w, val)
+	}
+}
+
+func TestTreeMarshalBinaryValues(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("nil"), nil)
+	tree.Insert(Key("int"), 42)
+	tree.Insert(Key("string"), "value")
+	tree.Insert(Key("bytes"), []byte{0, 1, 2})
+	tree.Insert(Key{}, "empty key")
+	tree.Insert(Key{0, 0xff}, 1.5)
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded := newTree()
+	loaded.Insert(Key("stale"), "must be replaced")
+	require.NoError(t, loaded.UnmarshalBinary(data))
+
+	assert.Equal(t, tree.Size(), loaded.Size())
+
+	_, found := loaded.Search(Key("stale"))
+	assert.False(t, found)
+
+	tree.ForEach(func(node Node) bool {
+		val, found := loaded.Search(node.Key())
+		assert.True(t, found, string(node.Key()))
+		assert.Equal(t, node.Value(), val)
+
+		return true
+	})
+}
+
+func TestTreeUnmarshalBinaryInvalid(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	assert.ErrorIs(t, tree.UnmarshalBinary(nil), ErrInvalidSnapshot)
+	assert.ErrorIs(t, tree.UnmarshalBinary([]byte("JSON")), ErrInvalidSnapshot)
+	assert.ErrorIs(t, tree.UnmarshalBinary(snapshotMagic[:]), ErrInvalidSnapshot)
+}
+
+func TestTreeGobEmbedded(t *testing.T) {
+	t.Parallel()
+
+	type message struct {
+		Name  string
+		Index Tree
+	}
+
+	RegisterGob()
+
+	src := message{Name: "index", Index: New()}
+	src.Index.Insert(Key("apple"), "fruit")
+	src.Index.Insert(Key("carrot"), "vegetable")
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(src))
+
+	var dst message
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&dst))
+
+	assert.Equal(t, "index", dst.Name)
+	require.NotNil(t, dst.Index)
+	assert.Equal(t, 2, dst.Index.Size())
+
+	val, found := dst.Index.Search(Key("carrot"))
+	assert.True(t, found)
+	assert.Equal(t, "vegetable", val)
+}
+
+func TestTreeMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tree := New()
+	tree.Insert(Key("b"), 2)
+	tree.Insert(Key("a"), "one")
+	tree.Insert(Key("c\\d"), true)
+	tree.Insert(Key{'x', 0xff}, nil)
+	tree.Insert(Key("日本"), 3)
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"one","b":2,"c\\\\d":true,"x\\xff":null,"日本":3}`, string(data))
+
+	loaded := New()
+	require.NoError(t, json.Unmarshal(data, loaded))
+	assert.Equal(t, tree.Size(), loaded.Size())
+
+	for key, expected := range map[string]interface{}{"a": "one", "b": 2.0, "c\\d": true, "x\xff": nil, "日本": 3.0} {
+		val, found := loaded.Search(Key(key))
+		assert.True(t, found, key)
+		assert.Equal(t, expected, val, key)
+	}
+}
+
+func TestTreeMarshalJSONBase64Keys(t *testing.T) {
+	t.Parallel()
+
+	tree := New()
+	tree.Insert(Key{0, 1, 2}, "binary")
+	tree.Insert(Key("text"), "text")
+
+	data, err := MarshalTreeJSON(tree, WithJSONKeyEncoding(JSONKeyBase64))
+	require.NoError(t, err)
+	assert.Equal(t, `{"AAEC":"binary","dGV4dA==":"text"}`, string(data))
+
+	loaded := New()
+	require.NoError(t, UnmarshalTreeJSON(loaded, data, WithJSONKeyEncoding(JSONKeyBase64)))
+	assert.Equal(t, 2, loaded.Size())
+
+	val, found := loaded.Search(Key{0, 1, 2})
+	assert.True(t, found)
+	assert.Equal(t, "binary", val)
+
+	assert.ErrorIs(t, UnmarshalTreeJSON(loaded, []byte(`{"!":1}`), WithJSONKeyEncoding(JSONKeyBase64)), ErrInvalidSnapshot)
+	assert.ErrorIs(t, UnmarshalTreeJSON(loaded, []byte(`{"\\q":1}`)), ErrInvalidSnapshot)
+}
+
+func TestTreeUnmarshalUnsupportedTree(t *testing.T) {
+	t.Parallel()
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{New()}
+
+	err := UnmarshalTreeJSON(wrapped, []byte(`{}`))
+	require.ErrorIs(t, err, ErrUnsupportedTree)
+	assert.NotErrorIs(t, err, ErrInvalidSnapshot)
+
+	err = UnmarshalTree(wrapped, snapshotMagic[:])
+	require.ErrorIs(t, err, ErrUnsupportedTree)
+	assert.NotErrorIs(t, err, ErrInvalidSnapshot)
+}
+
+func TestMarshalTreeInterface(t *testing.T) {
+	t.Parallel()
+
+	var tree Tree = New()
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("b"), "two")
+
+	data, err := MarshalTree(tree)
+	require.NoError(t, err)
+
+	loaded := New()
+	require.NoError(t, UnmarshalTree(loaded, data))
+	assert.Equal(t, 2, loaded.Size())
+
+	val, found := loaded.Search(Key("b"))
+	assert.True(t, found)
+	assert.Equal(t, "two", val)
+
+	// a custom implementation of the Tree interface can be encoded.
+	data, err = MarshalTree(struct{ Tree }{tree})
+	require.NoError(t, err)
+	require.NoError(t, UnmarshalTree(loaded, data))
+	assert.Equal(t, 2, loaded.Size())
+}
+
+func TestTreeMarshalJSONEmpty(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(New())
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
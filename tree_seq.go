@@ -0,0 +1,141 @@
+//go:build go1.23
+
+package art
+
+import (
+	"bytes"
+	"iter"
+	"sort"
+)
+
+// Sequences contains the range-over-func iterators of the tree.
+// They are available with Go 1.23 or newer.
+//
+//	for key, value := range tree.All() {
+//		...
+//	}
+//
+// Breaking out of the loop stops the underlying traversal.
+type Sequences interface {
+	// All returns the keys and values in ascending order.
+	All() iter.Seq2[Key, Value]
+
+	// Backward returns the keys and values in descending order.
+	Backward() iter.Seq2[Key, Value]
+
+	// Prefix returns the keys starting with the prefix and their values in ascending order.
+	Prefix(prefix Key) iter.Seq2[Key, Value]
+
+	// Range returns the keys in the range [start, end) and their values in ascending order.
+	// A nil end means that there is no upper bound.
+	Range(start, end Key) iter.Seq2[Key, Value]
+
+	// Keys returns the keys in ascending order.
+	Keys() iter.Seq[Key]
+
+	// Values returns the values in the ascending order of their keys.
+	Values() iter.Seq[Value]
+}
+
+// leafSeq returns the sequence of the leaves visited by forEach.
+func leafSeq(forEach func(callback Callback)) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		forEach(func(node Node) bool {
+			return yield(node.Key(), node.Value())
+		})
+	}
+}
+
+// All returns the keys and values in ascending order.
+func (tr *tree) All() iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) { tr.ForEach(callback) })
+}
+
+// Backward returns the keys and values in descending order.
+func (tr *tree) Backward() iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) { tr.ForEach(callback, TraverseReverse) })
+}
+
+// Prefix returns the keys starting with the prefix and their values in ascending order.
+func (tr *tree) Prefix(prefix Key) iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) { tr.ForEachPrefix(prefix, callback) })
+}
+
+// Range returns the keys in the range [start, end) and their values in ascending order.
+func (tr *tree) Range(start, end Key) iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) {
+		tr.forEachRange(tr.root, keyRange{start: start, end: end}, true, end != nil, callback, false)
+	})
+}
+
+// Keys returns the keys in ascending order.
+func (tr *tree) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		tr.ForEach(func(node Node) bool { return yield(node.Key()) })
+	}
+}
+
+// Values returns the values in the ascending order of their keys.
+func (tr *tree) Values() iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		tr.ForEach(func(node Node) bool { return yield(node.Value()) })
+	}
+}
+
+// All returns the keys and values in ascending order.
+func (ft *frozenTree) All() iter.Seq2[Key, Value] {
+	return ft.leafRange(0, len(ft.leaves), false)
+}
+
+// Backward returns the keys and values in descending order.
+func (ft *frozenTree) Backward() iter.Seq2[Key, Value] {
+	return ft.leafRange(0, len(ft.leaves), true)
+}
+
+// Prefix returns the keys starting with the prefix and their values in ascending order.
+func (ft *frozenTree) Prefix(prefix Key) iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) { ft.ForEachPrefix(prefix, callback) })
+}
+
+// Range returns the keys in the range [start, end) and their values in ascending order.
+func (ft *frozenTree) Range(start, end Key) iter.Seq2[Key, Value] {
+	from := sort.Search(len(ft.leaves), func(i int) bool {
+		return bytes.Compare(ft.key(i), start) >= 0
+	})
+
+	to := len(ft.leaves)
+	if end != nil {
+		to = sort.Search(len(ft.leaves), func(i int) bool {
+			return bytes.Compare(ft.key(i), end) >= 0
+		})
+	}
+
+	return ft.leafRange(from, ternary(to < from, from, to), false)
+}
+
+// Keys returns the keys in ascending order.
+func (ft *frozenTree) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for i := range ft.leaves {
+			if !yield(ft.key(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the values in the ascending order of their keys.
+func (ft *frozenTree) Values() iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		for _, value := range ft.values {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// leafRange returns the sequence of the leaves in [from, to).
+func (ft *frozenTree) leafRange(from, to int, reverse bool) iter.Seq2[Key, Value] {
+	return leafSeq(func(callback Callback) { ft.forEachLeaf(from, to, callback, reverse) })
+}
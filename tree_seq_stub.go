@@ -0,0 +1,7 @@
+//go:build !go1.23
+
+package art
+
+// Sequences contains the range-over-func iterators of the tree.
+// They are available with Go 1.23 or newer, see tree_seq.go.
+type Sequences interface{}
@@ -0,0 +1,138 @@
+//go:build go1.23
+
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeSequences(t *testing.T) {
+	t.Parallel()
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				keys   []string
+				values []Value
+			)
+
+			for key, value := range tree.All() {
+				keys = append(keys, string(key))
+				values = append(values, value)
+			}
+
+			assert.Equal(t, []string{"a", "ab", "abc", "b", "ba", "c"}, keys)
+			assert.Equal(t, []Value{"a", "ab", "abc", "b", "ba", "c"}, values)
+
+			keys = nil
+			for key := range tree.Backward() {
+				keys = append(keys, string(key))
+			}
+
+			assert.Equal(t, []string{"c", "ba", "b", "abc", "ab", "a"}, keys)
+
+			keys = nil
+			for key := range tree.Prefix(Key("ab")) {
+				keys = append(keys, string(key))
+			}
+
+			assert.Equal(t, []string{"ab", "abc"}, keys)
+
+			keys = nil
+			for key := range tree.Keys() {
+				keys = append(keys, string(key))
+			}
+
+			assert.Equal(t, []string{"a", "ab", "abc", "b", "ba", "c"}, keys)
+
+			values = nil
+			for value := range tree.Values() {
+				values = append(values, value)
+			}
+
+			assert.Equal(t, []Value{"a", "ab", "abc", "b", "ba", "c"}, values)
+		})
+	}
+}
+
+func TestTreeSequencesRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		start, end Key
+		want       []string
+	}{
+		{Key("ab"), Key("b"), []string{"ab", "abc"}},
+		{Key("abb"), Key("bb"), []string{"abc", "b", "ba"}},
+		{nil, Key("ab"), []string{"a"}},
+		{Key("b"), nil, []string{"b", "ba", "c"}},
+		{nil, nil, []string{"a", "ab", "abc", "b", "ba", "c"}},
+		{Key("c"), Key("a"), nil},
+		{Key("d"), nil, nil},
+	}
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		for _, tt := range tests {
+			var keys []string
+			for key := range tree.Range(tt.start, tt.end) {
+				keys = append(keys, string(key))
+			}
+
+			assert.Equal(t, tt.want, keys, "%s [%q, %q)", name, tt.start, tt.end)
+		}
+	}
+}
+
+func TestTreeSequencesBreak(t *testing.T) {
+	t.Parallel()
+
+	for name, tree := range readOnlyTrees("a", "ab", "abc", "b", "ba", "c") {
+		seqs := map[string]func(yield func(Key, Value) bool){
+			"All":      tree.All(),
+			"Backward": tree.Backward(),
+			"Prefix":   tree.Prefix(Key("a")),
+			"Range":    tree.Range(nil, nil),
+			"Keys": func(yield func(Key, Value) bool) {
+				tree.Keys()(func(key Key) bool { return yield(key, nil) })
+			},
+			"Values": func(yield func(Key, Value) bool) {
+				tree.Values()(func(value Value) bool { return yield(nil, value) })
+			},
+		}
+
+		for seqName, seq := range seqs {
+			count := 0
+			for range seq {
+				count++
+				if count == 2 {
+					break
+				}
+			}
+
+			// the loop panics if the sequence calls yield after break.
+			assert.Equal(t, 2, count, "%s %s", name, seqName)
+		}
+	}
+}
+
+func TestTreeSequencesModifyAfterBreak(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for i := 0; i < 10; i++ {
+		tree.Insert(Key{byte('a' + i)}, i)
+	}
+
+	// the sequences have no iterator state, so the tree can be modified after break.
+	for key := range tree.All() {
+		tree.Delete(key)
+
+		break
+	}
+
+	assert.Equal(t, 9, tree.Size())
+}
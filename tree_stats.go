@@ -0,0 +1,254 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// TreeStats contains the statistics of the tree structure and its estimated memory usage.
+type TreeStats struct {
+	// Nodes is the number of nodes of each kind, including leaves.
+	Nodes map[Kind]int
+
+	// Leaves is the number of leaves, which is equal to the tree size.
+	Leaves int
+
+	// MinDepth, MaxDepth and AvgDepth describe the depth of the leaves.
+	// The depth of a leaf is the number of inner nodes on the path from the root to the leaf.
+	MinDepth int
+	MaxDepth int
+	AvgDepth float64
+
+	// DepthHistogram maps a depth to the number of leaves at that depth.
+	DepthHistogram map[int]int
+
+	// PrefixLenHistogram maps a prefix length to the number of inner nodes with that prefix length.
+	PrefixLenHistogram map[int]int
+
+	// AvgFanout is the average number of children of the inner nodes of each kind.
+	// The zero byte child is counted as a regular child.
+	AvgFanout map[Kind]float64
+
+	// NodeBytes is the estimated memory used by the node structures, including leaves.
+	NodeBytes int
+
+	// KeyBytes is the memory used by the keys stored in the leaves.
+	KeyBytes int
+
+	// PrefixBytes is the memory used by the prefixes stored in the inner nodes.
+	// Only the first maxPrefixLen bytes of a prefix are stored, they are part of NodeBytes.
+	PrefixBytes int
+
+	// TotalBytes is the estimated memory used by the tree, NodeBytes + KeyBytes.
+	// Memory used by the values is not included.
+	TotalBytes int
+}
+
+// Stats returns the statistics of the tree structure and its estimated memory usage.
+// It visits every node, so it takes time proportional to the tree size.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func Stats(t Tree) (TreeStats, error) {
+	tr, ok := t.(*tree)
+	if !ok {
+		return TreeStats{}, fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	return tr.stats(), nil
+}
+
+// stats returns the statistics of the tree.
+func (tr *tree) stats() TreeStats {
+	stats := TreeStats{
+		Nodes:              make(map[Kind]int),
+		DepthHistogram:     make(map[int]int),
+		PrefixLenHistogram: make(map[int]int),
+		AvgFanout:          make(map[Kind]float64),
+	}
+
+	if tr.root == nil {
+		return stats
+	}
+
+	children := make(map[Kind]int)
+	depthSum := 0
+	stats.MinDepth = -1
+
+	var walk func(nr *nodeRef, depth int)
+	walk = func(nr *nodeRef, depth int) {
+		stats.Nodes[nr.kind]++
+		stats.NodeBytes += nr.sizeOf()
+
+		if nr.isLeaf() {
+			stats.Leaves++
+			stats.KeyBytes += len(nr.leaf().key)
+			stats.DepthHistogram[depth]++
+			depthSum += depth
+
+			if stats.MinDepth < 0 || depth < stats.MinDepth {
+				stats.MinDepth = depth
+			}
+
+			if depth > stats.MaxDepth {
+				stats.MaxDepth = depth
+			}
+
+			return
+		}
+
+		n := nr.node()
+		stats.PrefixLenHistogram[int(n.prefixLen)]++
+		stats.PrefixBytes += minInt(int(n.prefixLen), maxPrefixLen)
+
+		if zc := nr.zeroChild(); zc != nil {
+			children[nr.kind]++
+			walk(zc, depth+1)
+		}
+
+		nr.forEachChild(func(_ byte, child *nodeRef) {
+			children[nr.kind]++
+			walk(child, depth+1)
+		})
+	}
+
+	walk(tr.root, 0)
+
+	stats.AvgDepth = float64(depthSum) / float64(stats.Leaves)
+	stats.TotalBytes = stats.NodeBytes + stats.KeyBytes
+
+	for kind, count := range stats.Nodes {
+		if kind != Leaf {
+			stats.AvgFanout[kind] = float64(children[kind]) / float64(count)
+		}
+	}
+
+	return stats
+}
+
+// sizeOf returns the estimated memory used by the node structure and its reference.
+// The memory used by the leaf key and value is not included.
+func (nr *nodeRef) sizeOf() int {
+	size := unsafe.Sizeof(nodeRef{})
+
+	switch nr.kind {
+	case Leaf:
+		size += unsafe.Sizeof(leaf{})
+	case Node4:
+		size += unsafe.Sizeof(node4{})
+	case Node16:
+		size += unsafe.Sizeof(node16{})
+	case Node48:
+		size += unsafe.Sizeof(node48{})
+	case Node256:
+		size += unsafe.Sizeof(node256{})
+	}
+
+	return int(size)
+}
+
+// PrefixMemory contains the estimated memory used by the keys with the same prefix.
+type PrefixMemory struct {
+	// Prefix is the key prefix. It is shorter than the requested depth
+	// for the inner nodes shared by several prefixes and for the short keys.
+	Prefix Key
+
+	// Bytes is the estimated memory used by the nodes and keys, see TreeStats.TotalBytes.
+	Bytes int
+
+	// Leaves is the number of keys with the prefix.
+	Leaves int
+}
+
+// MemoryByPrefix returns the estimated memory usage aggregated by the key prefixes
+// of the given length, sorted by Bytes in descending order.
+// Inner nodes shared by several prefixes are reported under their own shorter prefix.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func MemoryByPrefix(t Tree, depth int) ([]PrefixMemory, error) {
+	tr, ok := t.(*tree)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	return tr.memoryByPrefix(depth), nil
+}
+
+// memoryByPrefix returns the memory usage aggregated by the key prefixes of the given length.
+func (tr *tree) memoryByPrefix(depth int) []PrefixMemory {
+	if tr.root == nil {
+		return nil
+	}
+
+	if depth < 0 {
+		depth = 0
+	}
+
+	buckets := make(map[string]*PrefixMemory)
+	bucketOf := func(prefix Key) *PrefixMemory {
+		pm, ok := buckets[string(prefix)]
+		if !ok {
+			pm = &PrefixMemory{Prefix: append(Key{}, prefix...)}
+			buckets[string(prefix)] = pm
+		}
+
+		return pm
+	}
+
+	// walk adds the node to the bucket, a nil bucket means that
+	// the node is above the requested depth and the bucket must be found.
+	var walk func(nr *nodeRef, keyOffset int, bucket *PrefixMemory)
+	walk = func(nr *nodeRef, keyOffset int, bucket *PrefixMemory) {
+		if nr.isLeaf() {
+			l := nr.leaf()
+			if bucket == nil {
+				bucket = bucketOf(l.key[:minInt(len(l.key), depth)])
+			}
+
+			bucket.Bytes += nr.sizeOf() + len(l.key)
+			bucket.Leaves++
+
+			return
+		}
+
+		keyOffset += int(nr.node().prefixLen)
+
+		// the inner node is shared by all keys with the same first keyOffset bytes.
+		childBucket := bucket
+		if bucket == nil {
+			path := nr.minimum().key
+			if keyOffset >= depth {
+				childBucket = bucketOf(path[:depth])
+				bucket = childBucket
+			} else {
+				bucket = bucketOf(path[:keyOffset])
+			}
+		}
+
+		bucket.Bytes += nr.sizeOf()
+
+		if zc := nr.zeroChild(); zc != nil {
+			walk(zc, keyOffset, childBucket)
+		}
+
+		nr.forEachChild(func(_ byte, child *nodeRef) {
+			walk(child, keyOffset+1, childBucket)
+		})
+	}
+
+	walk(tr.root, 0, nil)
+
+	result := make([]PrefixMemory, 0, len(buckets))
+	for _, pm := range buckets {
+		result = append(result, *pm)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Bytes != result[j].Bytes {
+			return result[i].Bytes > result[j].Bytes
+		}
+
+		return bytes.Compare(result[i].Prefix, result[j].Prefix) < 0
+	})
+
+	return result
+}
@@ -0,0 +1,170 @@
+package art
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeStatsEmpty(t *testing.T) {
+	t.Parallel()
+
+	stats, err := Stats(New())
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Leaves)
+	assert.Empty(t, stats.Nodes)
+	assert.Empty(t, stats.DepthHistogram)
+	assert.Zero(t, stats.TotalBytes)
+}
+
+func TestTreeStatsSmall(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for _, key := range []string{"a", "ab", "abc", "b", "prefix-one", "prefix-two"} {
+		tree.Insert(Key(key), key)
+	}
+
+	stats := tree.stats()
+
+	// Node4 ─┬─ Node4 ─┬─ Leaf(a)
+	//        │         └─ Node4 ─┬─ Leaf(ab)
+	//        │                   └─ Leaf(abc)
+	//        ├─ Leaf(b)
+	//        └─ Node4(refix-) ─┬─ Leaf(prefix-one)
+	//                          └─ Leaf(prefix-two)
+	assert.Equal(t, map[Kind]int{Leaf: 6, Node4: 4}, stats.Nodes)
+	assert.Equal(t, 6, stats.Leaves)
+	assert.Equal(t, 1, stats.MinDepth)
+	assert.Equal(t, 3, stats.MaxDepth)
+	assert.InDelta(t, 13.0/6.0, stats.AvgDepth, 1e-9)
+	assert.Equal(t, map[int]int{1: 1, 2: 3, 3: 2}, stats.DepthHistogram)
+	assert.Equal(t, map[int]int{0: 3, 6: 1}, stats.PrefixLenHistogram)
+	assert.Equal(t, map[Kind]float64{Node4: 9.0 / 4.0}, stats.AvgFanout)
+
+	nodeRefSize := int(unsafe.Sizeof(nodeRef{}))
+	expectedNodeBytes := 6*(nodeRefSize+int(unsafe.Sizeof(leaf{}))) + 4*(nodeRefSize+int(unsafe.Sizeof(node4{})))
+	assert.Equal(t, expectedNodeBytes, stats.NodeBytes)
+	assert.Equal(t, 1+2+3+1+10+10, stats.KeyBytes)
+	assert.Equal(t, 6, stats.PrefixBytes)
+	assert.Equal(t, stats.NodeBytes+stats.KeyBytes, stats.TotalBytes)
+}
+
+func TestTreeStatsSingleLeaf(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("key"), "value")
+
+	stats := tree.stats()
+	assert.Equal(t, map[Kind]int{Leaf: 1}, stats.Nodes)
+	assert.Equal(t, 0, stats.MinDepth)
+	assert.Equal(t, 0, stats.MaxDepth)
+	assert.Equal(t, map[int]int{0: 1}, stats.DepthHistogram)
+	assert.Empty(t, stats.AvgFanout)
+}
+
+func TestTreeStatsWords(t *testing.T) {
+	t.Parallel()
+
+	tree, words := treeWithData("test/assets/words.txt")
+	stats := tree.stats()
+
+	expected := collectStats(tree.Iterator(TraverseAll))
+	assert.Equal(t, expected.leafCount, stats.Nodes[Leaf])
+	assert.Equal(t, expected.node4Count, stats.Nodes[Node4])
+	assert.Equal(t, expected.node16Count, stats.Nodes[Node16])
+	assert.Equal(t, expected.node48Count, stats.Nodes[Node48])
+	assert.Equal(t, expected.node256Count, stats.Nodes[Node256])
+	assert.Equal(t, len(words), stats.Leaves)
+
+	leaves, inner, keyBytes := 0, 0, 0
+	for _, count := range stats.DepthHistogram {
+		leaves += count
+	}
+
+	for _, count := range stats.PrefixLenHistogram {
+		inner += count
+	}
+
+	for _, w := range words {
+		keyBytes += len(w)
+	}
+
+	assert.Equal(t, stats.Leaves, leaves)
+	assert.Equal(t, stats.Nodes[Node4]+stats.Nodes[Node16]+stats.Nodes[Node48]+stats.Nodes[Node256], inner)
+	assert.Equal(t, keyBytes, stats.KeyBytes)
+	assert.GreaterOrEqual(t, stats.AvgFanout[Node4], 2.0)
+	assert.LessOrEqual(t, stats.AvgFanout[Node4], 5.0)
+	assert.LessOrEqual(t, stats.MinDepth, stats.MaxDepth)
+}
+
+func TestTreeMemoryByPrefix(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for _, key := range []string{"tenantA:1", "tenantA:2", "tenantB:1", "x"} {
+		tree.Insert(Key(key), key)
+	}
+
+	node4Size := int(unsafe.Sizeof(nodeRef{}) + unsafe.Sizeof(node4{}))
+	leafSize := int(unsafe.Sizeof(nodeRef{}) + unsafe.Sizeof(leaf{}))
+
+	// the root and the "tenant" nodes are shared by several prefixes of length 7.
+	assert.Equal(t, []PrefixMemory{
+		{Prefix: Key("tenantA"), Bytes: node4Size + 2*leafSize + 18, Leaves: 2},
+		{Prefix: Key(""), Bytes: node4Size},
+		{Prefix: Key("tenant"), Bytes: node4Size},
+		{Prefix: Key("tenantB"), Bytes: leafSize + 9, Leaves: 1},
+		{Prefix: Key("x"), Bytes: leafSize + 1, Leaves: 1},
+	}, tree.memoryByPrefix(7))
+
+	assert.Equal(t, []PrefixMemory{
+		{Prefix: Key(""), Bytes: tree.stats().TotalBytes, Leaves: 4},
+	}, tree.memoryByPrefix(0))
+
+	result, err := MemoryByPrefix(New(), 1)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestTreeMemoryByPrefixWords(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := treeWithData("test/assets/words.txt")
+	stats := tree.stats()
+
+	for _, depth := range []int{1, 2, 3, 10} {
+		result := tree.memoryByPrefix(depth)
+
+		bytes, leaves := 0, 0
+		for i, pm := range result {
+			bytes += pm.Bytes
+			leaves += pm.Leaves
+
+			assert.LessOrEqual(t, len(pm.Prefix), depth)
+
+			if i > 0 {
+				assert.GreaterOrEqual(t, result[i-1].Bytes, pm.Bytes)
+			}
+		}
+
+		assert.Equal(t, stats.TotalBytes, bytes, depth)
+		assert.Equal(t, stats.Leaves, leaves, depth)
+	}
+}
+
+func TestTreeStatsUnsupportedTree(t *testing.T) {
+	t.Parallel()
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{New()}
+
+	_, err := Stats(wrapped)
+	assert.ErrorIs(t, err, ErrUnsupportedTree)
+
+	_, err = MemoryByPrefix(wrapped, 1)
+	assert.ErrorIs(t, err, ErrUnsupportedTree)
+}
@@ -3,8 +3,10 @@ package art
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -555,6 +557,32 @@ func TestInsertDeleteWithZeroChild(t *testing.T) {
 	assert.Nil(t, tree.root)
 }
 
+func TestShrinkNode256WithZeroChild(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("a"), "a") // zero child
+
+	for i := 0; i < node256Max; i++ {
+		tree.Insert(Key{'a', byte(i)}, i)
+	}
+
+	require.Equal(t, Node256, tree.root.kind)
+
+	// shrink the node256 to the node48 and further down to the leaf.
+	for i := 0; i < node256Max; i++ {
+		_, deleted := tree.Delete(Key{'a', byte(i)})
+		require.True(t, deleted)
+
+		v, found := tree.Search(Key("a"))
+		require.True(t, found, "zero child is lost after deleting %d, node %v", i, tree.root.kind)
+		assert.Equal(t, "a", v)
+	}
+
+	assert.Equal(t, Leaf, tree.root.kind)
+	assert.Equal(t, 1, tree.Size())
+}
+
 func TestTreeAPI(t *testing.T) { //nolint:funlen
 	t.Parallel()
 
@@ -969,3 +997,238 @@ func TestTreeInsertNilKeyTwice(t *testing.T) {
 	assert.Equal(t, knilv1, v)
 	assert.True(t, found)
 }
+
+// emptyKeyTestKeys are the keys mixed with the empty key, most of them are prefixes of other keys.
+var emptyKeyTestKeys = []string{"", "\x00", "a", "a\x00", "aa", "ab", "abc", "b"}
+
+// collectKeys returns the keys of the leaves visited by forEach.
+func collectKeys(forEach func(callback Callback)) []string {
+	keys := []string{}
+
+	forEach(func(node Node) bool {
+		keys = append(keys, string(node.Key()))
+
+		return true
+	})
+
+	return keys
+}
+
+// assertEmptyKeyTree checks the tree content against the sorted keys.
+func assertEmptyKeyTree(t *testing.T, tree *tree, keys []string) {
+	t.Helper()
+
+	require.NoError(t, Verify(tree))
+	assert.Equal(t, len(keys), tree.Size())
+
+	for _, key := range emptyKeyTestKeys {
+		idx := sort.SearchStrings(keys, key)
+		want := idx < len(keys) && keys[idx] == key
+
+		v, found := tree.Search(Key(key))
+		assert.Equal(t, want, found, "%q", key)
+
+		if want {
+			assert.Equal(t, key, v, "%q", key)
+		}
+	}
+
+	_, found := tree.Search(nil)
+	assert.Equal(t, len(keys) > 0 && keys[0] == "", found)
+
+	frozen := Freeze(tree)
+
+	for _, rt := range []ReadOnlyTree{tree, frozen} {
+		assert.Equal(t, keys, collectKeys(func(cb Callback) { rt.ForEach(cb) }))
+
+		reversed := collectKeys(func(cb Callback) { rt.ForEach(cb, TraverseReverse) })
+		for i, key := range reversed {
+			assert.Equal(t, keys[len(keys)-1-i], key)
+		}
+
+		for _, prefix := range []Key{nil, Key(""), Key("\x00"), Key("a"), Key("ab")} {
+			want := []string{}
+
+			for _, key := range keys {
+				if strings.HasPrefix(key, string(prefix)) {
+					want = append(want, key)
+				}
+			}
+
+			assert.Equal(t, want, collectKeys(func(cb Callback) { rt.ForEachPrefix(prefix, cb) }), "%q", prefix)
+		}
+	}
+
+	c := tree.newCursor()
+	if c.First() {
+		assert.Equal(t, keys[0], string(c.Key()))
+		if c.Next() {
+			assert.Equal(t, keys[1], string(c.Key()))
+			assert.True(t, c.Prev())
+			assert.Equal(t, keys[0], string(c.Key()))
+		} else {
+			assert.Len(t, keys, 1)
+		}
+	} else {
+		assert.Empty(t, keys)
+	}
+
+	assert.NotPanics(t, func() { _ = tree.String() })
+	assert.NoError(t, WriteDOT(&bytes.Buffer{}, tree))
+}
+
+func TestTreeEmptyKey(t *testing.T) {
+	t.Parallel()
+
+	// every subset of the keys containing the empty key, inserted in both orders
+	for mask := 1; mask < 1<<len(emptyKeyTestKeys); mask += 2 {
+		var keys []string
+
+		for i, key := range emptyKeyTestKeys {
+			if mask&(1<<i) != 0 {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, reverse := range []bool{false, true} {
+			order := append([]string(nil), keys...)
+			if reverse {
+				sort.Sort(sort.Reverse(sort.StringSlice(order)))
+			}
+
+			tree := newTree()
+			for _, key := range order {
+				tree.Insert(Key(key), key)
+			}
+
+			assertEmptyKeyTree(t, tree, keys)
+
+			// delete the empty key first or last, the nil key is the same key.
+			remaining := append([]string(nil), keys...)
+			for len(remaining) > 0 {
+				idx := ternary(reverse, len(remaining)-1, 0)
+				key := Key(remaining[idx])
+
+				if len(key) == 0 {
+					key = nil
+				}
+
+				v, deleted := tree.Delete(key)
+				require.True(t, deleted, "%q", key)
+				assert.Equal(t, remaining[idx], v)
+
+				_, deleted = tree.Delete(key)
+				assert.False(t, deleted, "%q", key)
+
+				remaining = append(remaining[:idx], remaining[idx+1:]...)
+				assertEmptyKeyTree(t, tree, remaining)
+			}
+
+			assert.Nil(t, tree.root)
+		}
+	}
+}
+
+func TestTreeEmptyKeyUpdate(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("a"), "a")
+
+	old, updated := tree.Insert(Key{}, 1)
+	assert.Nil(t, old)
+	assert.False(t, updated)
+
+	old, updated = tree.Insert(nil, 2)
+	assert.Equal(t, 1, old)
+	assert.True(t, updated)
+	assert.Equal(t, 2, tree.Size())
+
+	v, found := tree.Minimum()
+	assert.Equal(t, 2, v)
+	assert.True(t, found)
+
+	v, deleted := tree.Delete(Key{})
+	assert.Equal(t, 2, v)
+	assert.True(t, deleted)
+	assert.Equal(t, 1, tree.Size())
+}
+
+// longPrefixKey returns a key with the prefix of n bytes followed by the suffix.
+func longPrefixKey(n int, suffix string) Key {
+	key := bytes.Repeat([]byte{'p'}, n)
+
+	return append(key, suffix...)
+}
+
+func TestTreeLongSharedPrefix(t *testing.T) {
+	t.Parallel()
+
+	const mb = 1 << 20
+
+	keys := []Key{
+		longPrefixKey(3*mb, "a"),
+		longPrefixKey(3*mb, "b"),
+		longPrefixKey(3*mb, ""),                                    // zero child of the long prefix node
+		longPrefixKey(2*mb, "x"),                                   // splits the long prefix node
+		longPrefixKey(math.MaxUint16+1, "y"),                       // splits it right after the uint16 range
+		longPrefixKey(math.MaxUint16, "z"),                         // and right before it
+		append(longPrefixKey(4*mb, "c"), longPrefixKey(mb, "")...), // the longest key, 5MB
+	}
+
+	tree := newTree()
+	for i, key := range keys {
+		_, updated := tree.Insert(key, i)
+		require.False(t, updated)
+		require.NoError(t, Verify(tree), i)
+	}
+
+	sorted := append([]Key(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+
+	var visited []Key
+
+	tree.ForEach(func(node Node) bool {
+		visited = append(visited, node.Key())
+
+		return true
+	})
+	require.Equal(t, len(sorted), len(visited))
+
+	for i := range sorted {
+		assert.True(t, bytes.Equal(sorted[i], visited[i]), i)
+	}
+
+	frozen := Freeze(tree)
+
+	for i, key := range keys {
+		v, found := tree.Search(key)
+		assert.True(t, found, i)
+		assert.Equal(t, i, v)
+
+		v, found = frozen.Search(key)
+		assert.True(t, found, i)
+		assert.Equal(t, i, v)
+
+		c := tree.newCursor()
+		require.True(t, c.Seek(key), i)
+		assert.True(t, bytes.Equal(key, c.Key()), i)
+	}
+
+	for _, missing := range []Key{longPrefixKey(3*mb, "c"), longPrefixKey(mb, ""), longPrefixKey(math.MaxUint16+1, "")} {
+		_, found := tree.Search(missing)
+		assert.False(t, found)
+
+		_, found = frozen.Search(missing)
+		assert.False(t, found)
+	}
+
+	for i, key := range keys {
+		v, deleted := tree.Delete(key)
+		require.True(t, deleted, i)
+		assert.Equal(t, i, v)
+		require.NoError(t, Verify(tree), i)
+	}
+
+	assert.Equal(t, 0, tree.Size())
+}
@@ -1,5 +1,7 @@
 package art
 
+import "bytes"
+
 // traverseAction is an action to be taken during tree traversal.
 type traverseAction int
 
@@ -8,16 +10,6 @@ const (
 	traverseContinue                       // traverseContinue continues the tree traversal.
 )
 
-// traverseFunc defines the function for tree traversal.
-// It returns the index of the next child node to traverse.
-// The second return value indicates whether there are more child nodes to traverse.
-type traverseFunc func() (int, bool)
-
-// noopTraverseFunc is a no-op function for tree traversal.
-func noopTraverseFunc() (int, bool) {
-	return 0, false
-}
-
 // traverseOpts defines the options for tree traversal.
 type traverseOpts int
 
@@ -37,133 +29,43 @@ func (opts traverseOpts) hasReverse() bool {
 	return opts&TraverseReverse == TraverseReverse
 }
 
-// traverseContext is a context for traversing nodes with 4, 16, or 256 children.
-type traverseContext struct {
-	numChildren   int
-	zeroChildDone bool
-	curChildIdx   int
-}
-
-// ascTraversal traverses the children in ascending order.
-func (ctx *traverseContext) ascTraversal() (int, bool) {
-	if !ctx.zeroChildDone {
-		ctx.zeroChildDone = true
-
-		return ctx.numChildren, true
-	}
-
-	idx := ctx.curChildIdx
-	ctx.curChildIdx++
-
-	return idx, idx < ctx.numChildren
+func (opts traverseOpts) hasBreadthFirst() bool {
+	return opts&TraverseBreadthFirst == TraverseBreadthFirst
 }
 
-// descTraversal traverses the children in descending order.
-func (ctx *traverseContext) descTraversal() (int, bool) {
-	if ctx.curChildIdx >= 0 {
-		idx := ctx.curChildIdx
-		ctx.curChildIdx--
-
-		return idx, true
-	}
-
-	if !ctx.zeroChildDone {
-		ctx.zeroChildDone = true
-
-		return ctx.numChildren, true
-	}
-
-	return 0, false
+func (opts traverseOpts) hasResumable() bool {
+	return opts&TraverseResumable == TraverseResumable
 }
 
-// newTraverseGenericFunc creates a new traverseFunc for nodes with 4, 16, or 256 children.
-// The reverse parameter indicates whether to traverse the children in reverse order.
-func newTraverseGenericFunc(numChildren int, reverse bool) traverseFunc {
-	ctx := &traverseContext{
-		numChildren:   numChildren,
-		zeroChildDone: false,
-		curChildIdx:   ternary(reverse, numChildren-1, 0),
-	}
-
-	return ternary(reverse, ctx.descTraversal, ctx.ascTraversal)
+// traverseFrame is the traversal position among the children of one node.
+// It is a value type, so the traversal stacks do not allocate per visited node.
+type traverseFrame struct {
+	nr  *nodeRef
+	ord int // ordinal of the current child, see childAtOrdinal
 }
 
-// traverse48Context is a context for traversing nodes with 48 children.
-type traverse48Context struct {
-	curKeyIdx     int
-	curKeyCh      byte
-	zeroChildDone bool
-	n48           *node48
+// newTraverseFrame creates a frame positioned before the first child in the traversal order.
+func newTraverseFrame(nr *nodeRef, forward bool) traverseFrame {
+	return traverseFrame{nr: nr, ord: ternary(forward, -1, nr.maxChildOrdinal()+1)}
 }
 
-// ascTraversal traverses the children in ascending order.
-func (ctx *traverse48Context) ascTraversal() (int, bool) {
-	if !ctx.zeroChildDone {
-		ctx.zeroChildDone = true
+// step moves the frame to the next (or previous) child and returns it.
+// It returns nil if there are no more children in that direction.
+func (f *traverseFrame) step(forward bool) *nodeRef {
+	maxOrd := f.nr.maxChildOrdinal()
 
-		return node48Max, true
-	}
-
-	for ; ctx.curKeyIdx < node256Max; ctx.curKeyIdx++ {
-		if ctx.n48.hasChild(ctx.curKeyIdx) {
-			ctx.curKeyCh = ctx.n48.keys[ctx.curKeyIdx]
-			ctx.curKeyIdx++
+	for {
+		f.ord += ternary(forward, 1, -1)
+		if f.ord < 0 || f.ord > maxOrd {
+			f.ord = ternary(forward, maxOrd+1, -1)
 
-			return int(ctx.curKeyCh), true
+			return nil
 		}
-	}
 
-	return 0, false
-}
-
-// descTraversal traverses the children in descending order.
-func (ctx *traverse48Context) descTraversal() (int, bool) {
-	for ; ctx.curKeyIdx >= 0; ctx.curKeyIdx-- {
-		if ctx.n48.hasChild(ctx.curKeyIdx) {
-			ctx.curKeyCh = ctx.n48.keys[ctx.curKeyIdx]
-			ctx.curKeyIdx--
-
-			return int(ctx.curKeyCh), true
+		if child := f.nr.childAtOrdinal(f.ord); child != nil {
+			return child
 		}
 	}
-
-	if !ctx.zeroChildDone {
-		ctx.zeroChildDone = true
-
-		return node48Max, true
-	}
-
-	return 0, false
-}
-
-// newTraverse48Func creates a new traverseFunc for nodes with 48 children.
-// The reverse parameter indicates whether to traverse the children in reverse order.
-func newTraverse48Func(n48 *node48, reverse bool) traverseFunc {
-	ctx := &traverse48Context{
-		curKeyIdx: ternary(reverse, node256Max-1, 0),
-		n48:       n48,
-	}
-
-	return ternary(reverse, ctx.descTraversal, ctx.ascTraversal)
-}
-
-func newTraverseFunc(n *nodeRef, reverse bool) traverseFunc {
-	if n == nil {
-		return noopTraverseFunc
-	}
-
-	switch n.kind { //nolint:exhaustive
-	case Node4:
-		return newTraverseGenericFunc(node4Max, reverse)
-	case Node16:
-		return newTraverseGenericFunc(node16Max, reverse)
-	case Node48:
-		return newTraverse48Func(n.node48(), reverse)
-	case Node256:
-		return newTraverseGenericFunc(node256Max, reverse)
-	default:
-		return noopTraverseFunc
-	}
 }
 
 func mergeOptions(options ...int) int {
@@ -183,7 +85,7 @@ func traverseOptions(options ...int) traverseOpts {
 		typeOpts = TraverseLeaf // By default filter only leafs
 	}
 
-	orderOpts := opts & TraverseReverse
+	orderOpts := opts & (TraverseReverse | TraverseBreadthFirst | TraverseResumable)
 
 	return traverseOpts(typeOpts | orderOpts)
 }
@@ -206,6 +108,18 @@ func traverseFilter(opts traverseOpts, callback Callback) Callback {
 	}
 }
 
+// forEachNext calls the callback for the nodes returned by the iterator.
+// The iteration stops at the first error, e.g. if the callback has modified the tree,
+// so the callback never gets a nil node.
+func forEachNext(it Iterator, callback Callback) {
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil || !callback(node) {
+			return
+		}
+	}
+}
+
 func (tr *tree) forEachRecursively(current *nodeRef, callback Callback, reverse bool) traverseAction {
 	if current == nil {
 		return traverseContinue
@@ -215,23 +129,10 @@ func (tr *tree) forEachRecursively(current *nodeRef, callback Callback, reverse
 		return traverseStop
 	}
 
-	nextFn := newTraverseFunc(current, reverse)
-	children := toNode(current).allChildren()
-
-	return tr.traverseChildren(nextFn, children, callback, reverse)
-}
-
-func (tr *tree) traverseChildren(nextFn traverseFunc, children []*nodeRef, cb Callback, reverse bool) traverseAction {
-	for {
-		idx, hasMore := nextFn()
-		if !hasMore {
-			break
-		}
-
-		if child := children[idx]; child != nil {
-			if tr.forEachRecursively(child, cb, reverse) == traverseStop {
-				return traverseStop
-			}
+	frame := newTraverseFrame(current, !reverse)
+	for child := frame.step(!reverse); child != nil; child = frame.step(!reverse) {
+		if tr.forEachRecursively(child, callback, reverse) == traverseStop {
+			return traverseStop
 		}
 	}
 
@@ -256,3 +157,48 @@ func (tr *tree) forEachPrefix(key Key, callback Callback, opts int) traverseActi
 
 	return traverseContinue
 }
+
+// keyRange is the range of keys [start, end) visited by forEachRange.
+// A nil end means that there is no upper bound.
+type keyRange struct {
+	start Key
+	end   Key
+}
+
+// forEachRange calls the callback for the leaves with the keys in the range.
+// The subtrees entirely outside of the range are skipped. checkStart and checkEnd
+// are false if all keys of the subtree are known to be within the bound.
+func (tr *tree) forEachRange(nr *nodeRef, kr keyRange, checkStart, checkEnd bool, callback Callback, reverse bool) traverseAction {
+	if nr == nil {
+		return traverseContinue
+	}
+
+	if checkStart {
+		if bytes.Compare(nr.maximum().key, kr.start) < 0 {
+			return traverseContinue
+		}
+
+		checkStart = bytes.Compare(nr.minimum().key, kr.start) < 0
+	}
+
+	if checkEnd {
+		if bytes.Compare(nr.minimum().key, kr.end) >= 0 {
+			return traverseContinue
+		}
+
+		checkEnd = bytes.Compare(nr.maximum().key, kr.end) >= 0
+	}
+
+	if nr.isLeaf() {
+		return ternary(callback(nr), traverseContinue, traverseStop)
+	}
+
+	frame := newTraverseFrame(nr, !reverse)
+	for child := frame.step(!reverse); child != nil; child = frame.step(!reverse) {
+		if tr.forEachRange(child, kr, checkStart, checkEnd, callback, reverse) == traverseStop {
+			return traverseStop
+		}
+	}
+
+	return traverseContinue
+}
@@ -2,6 +2,7 @@ package art
 
 import (
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,6 +93,27 @@ func TestTreeTraversalCancelEarly(t *testing.T) {
 	assert.Equal(t, 5, count)
 }
 
+// TestIteratorAllocs checks that a full scan allocates only the iterator itself,
+// regardless of the number of visited nodes.
+// AllocsPerRun panics in parallel tests, so the test runs sequentially.
+func TestIteratorAllocs(t *testing.T) { //nolint:paralleltest
+	tree, _ := treeWithData("test/assets/words.txt")
+
+	for _, opts := range []int{TraverseLeaf, TraverseAll, TraverseAll | TraverseReverse} {
+		allocs := testing.AllocsPerRun(10, func() {
+			for it := tree.Iterator(opts); it.HasNext(); {
+				_, _ = it.Next()
+			}
+		})
+		assert.LessOrEqual(t, allocs, float64(4), "Iterator(%d)", opts)
+
+		allocs = testing.AllocsPerRun(10, func() {
+			tree.ForEach(func(Node) bool { return true }, opts)
+		})
+		assert.LessOrEqual(t, allocs, float64(1), "ForEach(%d)", opts)
+	}
+}
+
 func TestTreeTraversalWordsStats(t *testing.T) {
 	t.Parallel()
 
@@ -672,3 +694,219 @@ func TestNode48ReverseIteratorMissingIndexZero(t *testing.T) {
 		}
 	}
 }
+
+func TestTreeIteratorRemove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts int
+	}{
+		{"Leaf", TraverseLeaf},
+		{"LeafReverse", TraverseLeaf | TraverseReverse},
+		{"All", TraverseAll},
+		{"AllReverse", TraverseAll | TraverseReverse},
+		{"BreadthFirst", TraverseAll | TraverseBreadthFirst},
+		{"Resumable", TraverseResumable},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree, words := treeWithData("test/assets/hsk_words.txt")
+			expected := make(map[string]bool, len(words))
+
+			for _, w := range words {
+				if len(w)%2 == 1 {
+					expected[string(w)] = true
+				}
+			}
+
+			leaves := 0
+
+			for it := tree.Iterator(tt.opts); it.HasNext(); {
+				node, err := it.Next()
+				require.NoError(t, err)
+
+				if node.Kind() != Leaf {
+					continue
+				}
+
+				leaves++
+
+				if len(node.Key())%2 == 0 {
+					require.NoError(t, it.Remove())
+				}
+			}
+
+			assert.Equal(t, len(expected), tree.Size())
+			assert.Equal(t, tree.Size()+countEven(words), leaves)
+			require.NoError(t, Verify(tree))
+
+			tree.ForEach(func(node Node) bool {
+				assert.True(t, expected[string(node.Key())], string(node.Key()))
+
+				return true
+			})
+		})
+	}
+}
+
+// countEven returns the number of unique words with an even length.
+func countEven(words [][]byte) int {
+	unique := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len(w)%2 == 0 {
+			unique[string(w)] = true
+		}
+	}
+
+	return len(unique)
+}
+
+func TestTreeIteratorRemoveAll(t *testing.T) {
+	t.Parallel()
+
+	for _, opts := range []int{TraverseLeaf, TraverseAll, TraverseAll | TraverseReverse, TraverseBreadthFirst} {
+		tree := newTree()
+		for i := 0; i < 300; i++ {
+			tree.Insert(Key(strconv.Itoa(i)), i)
+		}
+
+		var removed []string
+
+		for it := tree.Iterator(opts); it.HasNext(); {
+			node, err := it.Next()
+			require.NoError(t, err)
+
+			if node.Kind() == Leaf {
+				removed = append(removed, string(node.Key()))
+				require.NoError(t, it.Remove())
+			}
+		}
+
+		assert.Len(t, removed, 300, opts)
+		assert.Equal(t, 0, tree.Size(), opts)
+		assert.Nil(t, tree.root, opts)
+	}
+}
+
+func TestTreeIteratorRemoveShrink(t *testing.T) {
+	t.Parallel()
+
+	// groups of children of every node kind, with and without the zero byte child.
+	tree := newTree()
+	for _, size := range []int{1, 2, 3, 4, 5, 16, 17, 48, 49, 256} {
+		group := "group" + strconv.Itoa(size)
+		if size%2 == 1 {
+			tree.Insert(Key(group), group)
+		}
+
+		for i := 0; i < size; i++ {
+			key := group + string([]byte{byte(i)}) + "leaf"
+			tree.Insert(Key(key), key)
+		}
+	}
+
+	var keys []string
+
+	tree.ForEach(func(node Node) bool {
+		keys = append(keys, string(node.Key()))
+
+		return true
+	})
+
+	for _, opts := range []int{TraverseLeaf, TraverseLeaf | TraverseReverse, TraverseAll, TraverseAll | TraverseReverse} {
+		for _, step := range []int{1, 2, 3, 7} {
+			tr := newTree()
+			for _, key := range keys {
+				tr.Insert(Key(key), key)
+			}
+
+			var visited, remaining []string
+
+			for it := tr.Iterator(opts); it.HasNext(); {
+				node, err := it.Next()
+				require.NoError(t, err)
+
+				if node.Kind() != Leaf {
+					continue
+				}
+
+				if len(visited)%step == 0 {
+					require.NoError(t, it.Remove())
+				} else {
+					remaining = append(remaining, string(node.Key()))
+				}
+
+				visited = append(visited, string(node.Key()))
+			}
+
+			if opts&TraverseReverse == TraverseReverse {
+				for i, j := 0, len(visited)-1; i < j; i, j = i+1, j-1 {
+					visited[i], visited[j] = visited[j], visited[i]
+				}
+
+				for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+					remaining[i], remaining[j] = remaining[j], remaining[i]
+				}
+			}
+
+			require.Equal(t, keys, visited, "opts %d, step %d", opts, step)
+			require.NoError(t, Verify(tr))
+
+			var got []string
+
+			tr.ForEach(func(node Node) bool {
+				got = append(got, string(node.Key()))
+
+				return true
+			})
+			assert.Equal(t, remaining, got, "opts %d, step %d", opts, step)
+		}
+	}
+}
+
+func TestTreeIteratorRemoveErrors(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for _, key := range []string{"a", "b", "c"} {
+		tree.Insert(Key(key), key)
+	}
+
+	for _, opts := range []int{TraverseLeaf, TraverseAll, TraverseBreadthFirst | TraverseAll, TraverseResumable} {
+		it := tree.Iterator(opts)
+		assert.Equal(t, ErrNoLeafToRemove, it.Remove(), opts)
+	}
+
+	it := tree.Iterator(TraverseAll)
+	_, err := it.Next()
+	require.NoError(t, err)
+	assert.Equal(t, ErrNoLeafToRemove, it.Remove(), "root is not a leaf")
+
+	it = tree.Iterator()
+	_, err = it.Next()
+	require.NoError(t, err)
+	require.NoError(t, it.Remove())
+	assert.Equal(t, ErrNoLeafToRemove, it.Remove(), "already removed")
+
+	tree.Insert(Key("d"), "d")
+	assert.Equal(t, ErrConcurrentModification, it.Remove())
+
+	it = tree.Iterator(TraverseBreadthFirst)
+	_, err = it.Next()
+	require.NoError(t, err)
+	tree.Insert(Key("e"), "e")
+	assert.Equal(t, ErrConcurrentModification, it.Remove())
+
+	frozen := Freeze(tree)
+	for _, opts := range []int{TraverseLeaf, TraverseBreadthFirst} {
+		it = frozen.Iterator(opts)
+		_, err = it.Next()
+		require.NoError(t, err)
+		assert.Equal(t, ErrReadOnlyTree, it.Remove(), opts)
+	}
+}
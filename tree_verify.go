@@ -0,0 +1,266 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// VerifyError describes the first violated tree invariant found by Verify.
+type VerifyError struct {
+	// Path is the key prefix leading to the invalid node.
+	Path Key
+
+	// Kind is the kind of the invalid node.
+	Kind Kind
+
+	// Reason describes the violated invariant.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("%s: %s at path %q: %s", ErrTreeCorrupted, e.Kind, e.Path, e.Reason)
+}
+
+// Unwrap returns ErrTreeCorrupted, so the error can be checked with errors.Is.
+func (e *VerifyError) Unwrap() error {
+	return ErrTreeCorrupted
+}
+
+// nodeBounds contains the allowed number of children for each inner node kind.
+//
+//nolint:gochecknoglobals
+var nodeBounds = map[Kind][2]int{
+	Node4:   {node4Min, node4Max},
+	Node16:  {node16Min, node16Max},
+	Node48:  {node48Min, node48Max},
+	Node256: {node256Min, node256Max},
+}
+
+// treeVerifier walks the tree and checks the invariants of every node.
+type treeVerifier struct {
+	leaves int
+}
+
+// Verify checks the structural invariants of the tree and returns
+// a *VerifyError with the path to the first invalid node.
+// It is intended for debugging and tests, it visits every node.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func Verify(t Tree) error {
+	tr, ok := t.(*tree)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	return tr.verify()
+}
+
+// verify checks the tree invariants and returns a *VerifyError for the first violation.
+func (tr *tree) verify() error {
+	if tr.root == nil {
+		if tr.size != 0 {
+			return &VerifyError{Reason: fmt.Sprintf("size is %d, but the tree is empty", tr.size)}
+		}
+
+		return nil
+	}
+
+	v := &treeVerifier{}
+	if err := v.verify(tr.root, nil, 0); err != nil {
+		return err
+	}
+
+	if v.leaves != tr.size {
+		return &VerifyError{
+			Kind:   tr.root.kind,
+			Reason: fmt.Sprintf("size is %d, but the tree has %d leaves", tr.size, v.leaves),
+		}
+	}
+
+	return nil
+}
+
+// verify checks the node at keyOffset and its children recursively.
+// The path contains the key bytes leading to the node.
+func (v *treeVerifier) verify(nr *nodeRef, path Key, keyOffset int) error {
+	fail := func(format string, args ...interface{}) error {
+		return &VerifyError{Path: path, Kind: nr.kind, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if nr.ref == nil {
+		return fail("node reference is nil")
+	}
+
+	if nr.isLeaf() {
+		v.leaves++
+
+		if key := nr.leaf().key; len(key) < keyOffset || !bytes.Equal(key[:keyOffset], path) {
+			return fail("leaf key %q does not start with the path", key)
+		}
+
+		return nil
+	}
+
+	if err := v.verifyChildren(nr, fail); err != nil {
+		return err
+	}
+
+	n := nr.node()
+	end := keyOffset + int(n.prefixLen)
+
+	// all leaves share the path and the prefix, so it is enough to check the smallest and the largest.
+	minLeaf, maxLeaf := nr.minimum(), nr.maximum()
+	if minLeaf == nil || maxLeaf == nil {
+		return fail("node has no leaves")
+	}
+
+	if len(minLeaf.key) < end || len(maxLeaf.key) < end {
+		return fail("prefix length %d exceeds the key length of the leaves", n.prefixLen)
+	}
+
+	stored := minInt(int(n.prefixLen), maxPrefixLen)
+	if !bytes.Equal(n.prefix[:stored], minLeaf.key[keyOffset:keyOffset+stored]) {
+		return fail("prefix %q does not match the minimum leaf key %q", n.prefix[:stored], minLeaf.key)
+	}
+
+	if !bytes.Equal(minLeaf.key[:end], maxLeaf.key[:end]) {
+		return fail("leaves %q and %q do not share the node prefix", minLeaf.key, maxLeaf.key)
+	}
+
+	// use the full key for the path, the node stores only the first maxPrefixLen bytes of the prefix.
+	nodePath := append(append(Key{}, path...), minLeaf.key[keyOffset:end]...)
+
+	if zc := nr.zeroChild(); zc != nil {
+		if !zc.isLeaf() {
+			return fail("zero byte child is %s, expected Leaf", zc.kind)
+		}
+
+		if len(zc.leaf().key) != end {
+			return fail("zero byte child key %q does not end at the node", zc.leaf().key)
+		}
+
+		if err := v.verify(zc, nodePath, end); err != nil {
+			return err
+		}
+	}
+
+	var err error
+
+	nr.forEachChild(func(ch byte, child *nodeRef) {
+		if err == nil {
+			err = v.verify(child, append(nodePath[:len(nodePath):len(nodePath)], ch), end+1)
+		}
+	})
+
+	return err
+}
+
+// verifyChildren checks the children bookkeeping of the inner node.
+func (v *treeVerifier) verifyChildren(nr *nodeRef, fail func(format string, args ...interface{}) error) error {
+	n := nr.node()
+	childrenLen := int(n.childrenLen)
+	bounds := nodeBounds[nr.kind]
+
+	numChildren := childrenLen
+	if nr.kind == Node4 && nr.zeroChild() != nil {
+		numChildren++ // node4 counts the zero byte child to decide whether to shrink
+	}
+
+	if childrenLen > bounds[1] || numChildren < bounds[0] {
+		return fail("%d children is out of bounds [%d, %d]", childrenLen, bounds[0], bounds[1])
+	}
+
+	switch nr.kind { //nolint:exhaustive
+	case Node4:
+		n4 := nr.node4()
+
+		return verifySortedKeys(n4.keys[:], n4.children[:node4Max], childrenLen,
+			func(i int) bool { return n4.present[i] != 0 }, fail)
+
+	case Node16:
+		n16 := nr.node16()
+
+		return verifySortedKeys(n16.keys[:], n16.children[:node16Max], childrenLen, n16.hasChild, fail)
+
+	case Node48:
+		return verifyNode48(nr.node48(), fail)
+
+	case Node256:
+		count := 0
+
+		for _, child := range nr.node256().children[:node256Max] {
+			if child != nil {
+				count++
+			}
+		}
+
+		if count != childrenLen {
+			return fail("childrenLen is %d, but there are %d children", childrenLen, count)
+		}
+	}
+
+	return nil
+}
+
+// verifySortedKeys checks that the first childrenLen keys of node4/node16 are present,
+// sorted and have children, and that the remaining slots are empty.
+func verifySortedKeys(keys []byte, children []*nodeRef, childrenLen int, present func(int) bool,
+	fail func(format string, args ...interface{}) error,
+) error {
+	for i := range children {
+		if i >= childrenLen {
+			if children[i] != nil || present(i) {
+				return fail("slot %d is beyond childrenLen %d but is not empty", i, childrenLen)
+			}
+
+			continue
+		}
+
+		if children[i] == nil || !present(i) {
+			return fail("slot %d for key %d has no child", i, keys[i])
+		}
+
+		if i > 0 && keys[i-1] >= keys[i] {
+			return fail("keys %d and %d are not sorted", keys[i-1], keys[i])
+		}
+	}
+
+	return nil
+}
+
+// verifyNode48 checks that the present bits, the keys and the children of node48 agree.
+func verifyNode48(n *node48, fail func(format string, args ...interface{}) error) error {
+	var used [node48Max]bool
+
+	count := 0
+
+	for ch := 0; ch < node256Max; ch++ {
+		if !n.hasChild(ch) {
+			continue
+		}
+
+		idx := int(n.keys[ch])
+		if idx >= node48Max || n.children[idx] == nil {
+			return fail("key %d points to an empty slot %d", ch, idx)
+		}
+
+		if used[idx] {
+			return fail("key %d points to the slot %d used by another key", ch, idx)
+		}
+
+		used[idx] = true
+		count++
+	}
+
+	for idx, child := range n.children[:node48Max] {
+		if child != nil && !used[idx] {
+			return fail("slot %d has a child, but no key points to it", idx)
+		}
+	}
+
+	if count != int(n.childrenLen) {
+		return fail("childrenLen is %d, but there are %d present keys", n.childrenLen, count)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package art
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requireVerifyError asserts that the tree is corrupted and returns the verify error.
+func requireVerifyError(t *testing.T, tree *tree) *VerifyError {
+	t.Helper()
+
+	err := Verify(tree)
+	require.ErrorIs(t, err, ErrTreeCorrupted)
+
+	var verr *VerifyError
+	require.True(t, errors.As(err, &verr))
+
+	return verr
+}
+
+func TestTreeVerifyWordsWithDeletes(t *testing.T) {
+	t.Parallel()
+
+	tree, words := treeWithData("test/assets/words.txt")
+	require.NoError(t, Verify(tree))
+
+	for i, w := range words {
+		if i%2 == 0 {
+			tree.Delete(w)
+		}
+	}
+
+	require.NoError(t, Verify(tree))
+
+	for i, w := range words {
+		if i%2 == 1 {
+			tree.Delete(w)
+		}
+
+		if i%10000 == 0 {
+			require.NoError(t, Verify(tree), i)
+		}
+	}
+
+	assert.Equal(t, 0, tree.Size())
+	require.NoError(t, Verify(tree))
+}
+
+func TestTreeVerifyAllNodeKinds(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("x"), "x")
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{'x', byte(i)}, i)
+		require.NoError(t, Verify(tree), i)
+	}
+
+	for i := 255; i >= 0; i-- {
+		tree.Delete(Key{'x', byte(i)})
+		require.NoError(t, Verify(tree), i)
+	}
+}
+
+func TestTreeVerifyNode256ShrinkKeepsZeroChild(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	tree.Insert(Key("x"), "x")
+
+	for i := 0; i < node256Min; i++ {
+		tree.Insert(Key{'x', byte(i)}, i)
+	}
+
+	require.Equal(t, Node256, tree.root.kind)
+
+	tree.Delete(Key{'x', 0})
+	require.Equal(t, Node48, tree.root.kind)
+	require.NoError(t, Verify(tree))
+
+	val, found := tree.Search(Key("x"))
+	assert.True(t, found)
+	assert.Equal(t, "x", val)
+}
+
+func TestTreeVerifyDetectsCorruption(t *testing.T) {
+	t.Parallel()
+
+	newTestTree := func() *tree {
+		tree := newTree()
+		for _, key := range []string{"abc", "abd", "abe", "b"} {
+			tree.Insert(Key(key), key)
+		}
+
+		return tree
+	}
+
+	tree := newTestTree()
+	tree.size++
+	assert.Contains(t, requireVerifyError(t, tree).Reason, "size is 5")
+
+	tree = newTestTree()
+	tree.root.node4().keys[0], tree.root.node4().keys[1] = 'b', 'a'
+	verr := requireVerifyError(t, tree)
+	assert.Equal(t, Node4, verr.Kind)
+	assert.Empty(t, verr.Path)
+	assert.Contains(t, verr.Reason, "not sorted")
+
+	tree = newTestTree()
+	tree.root.node4().children[0].node().prefix[0] = 'z'
+	verr = requireVerifyError(t, tree)
+	assert.Equal(t, Key("a"), verr.Path)
+	assert.Contains(t, verr.Reason, "prefix")
+
+	tree = newTestTree()
+	tree.root.node4().children[0].node4().childrenLen = 2
+	verr = requireVerifyError(t, tree)
+	assert.Equal(t, Key("a"), verr.Path)
+	assert.Contains(t, verr.Reason, "beyond childrenLen")
+
+	tree = newTestTree()
+	tree.root.node4().children[node4Max] = tree.root.node4().children[0]
+	verr = requireVerifyError(t, tree)
+	assert.Contains(t, verr.Reason, "zero byte child is Node4")
+
+	tree = newTestTree()
+	leafRef := tree.root.node4().children[1]
+	leafRef.leaf().key = Key("c")
+	verr = requireVerifyError(t, tree)
+	assert.Equal(t, Leaf, verr.Kind)
+	assert.Equal(t, Key("b"), verr.Path)
+}
+
+func TestTreeVerifyNode48Corruption(t *testing.T) {
+	t.Parallel()
+
+	tree := newTree()
+	for i := 0; i < node48Min; i++ {
+		tree.Insert(Key{byte(i)}, i)
+	}
+
+	require.Equal(t, Node48, tree.root.kind)
+	require.NoError(t, Verify(tree))
+
+	n48 := tree.root.node48()
+	n48.keys[1] = n48.keys[0]
+	assert.Contains(t, requireVerifyError(t, tree).Reason, "used by another key")
+
+	n48.keys[1] = 1
+	n48.present.clearAt(1)
+	assert.Contains(t, requireVerifyError(t, tree).Reason, "no key points to it")
+}
+
+func TestTreeVerifyUnsupportedTree(t *testing.T) {
+	t.Parallel()
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{New()}
+
+	err := Verify(wrapped)
+	require.ErrorIs(t, err, ErrUnsupportedTree)
+	assert.NotErrorIs(t, err, ErrTreeCorrupted)
+}
@@ -0,0 +1,113 @@
+package art
+
+import "fmt"
+
+// WalkAction tells Walk how to proceed after a node is entered.
+type WalkAction int
+
+// Walk actions.
+const (
+	// WalkContinue continues the walk with the children of the node.
+	WalkContinue WalkAction = iota
+
+	// WalkSkipChildren skips the children of the node, Leave is still called for the node.
+	WalkSkipChildren
+
+	// WalkStop stops the walk immediately, Leave is not called for the pending nodes.
+	WalkStop
+)
+
+// Visitor is called by Walk for every node of the tree, including inner nodes.
+type Visitor interface {
+	// Enter is called before the children of the node are visited.
+	Enter(node Node) WalkAction
+
+	// Leave is called after the children of the node are visited or skipped.
+	Leave(node Node)
+}
+
+// WalkFuncs adapts a pair of functions to the Visitor interface.
+// A nil Enter continues the walk, a nil Leave does nothing.
+type WalkFuncs struct {
+	EnterFunc func(node Node) WalkAction
+	LeaveFunc func(node Node)
+}
+
+// Enter calls EnterFunc.
+func (w WalkFuncs) Enter(node Node) WalkAction {
+	if w.EnterFunc == nil {
+		return WalkContinue
+	}
+
+	return w.EnterFunc(node)
+}
+
+// Leave calls LeaveFunc.
+func (w WalkFuncs) Leave(node Node) {
+	if w.LeaveFunc != nil {
+		w.LeaveFunc(node)
+	}
+}
+
+// Walk visits all nodes of the tree in depth-first order, calling visitor.Enter
+// before and visitor.Leave after the children of each node, including leaves.
+// The action returned by Enter can skip the children of the node or stop the walk.
+// By default, the children are visited in ascending order,
+// pass the TraverseReverse option to visit them in descending order.
+// It returns ErrUnsupportedTree if the tree is not created by New or NewWithOptions.
+func Walk(t Tree, visitor Visitor, options ...int) error {
+	tr, ok := t.(*tree)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedTree, t)
+	}
+
+	tr.walk(visitor, options...)
+
+	return nil
+}
+
+// walk visits all nodes of the tree in depth-first order.
+func (tr *tree) walk(visitor Visitor, opts ...int) {
+	if tr.root == nil {
+		return
+	}
+
+	reverse := traverseOptions(opts...).hasReverse()
+	walkNode(tr.root, visitor, reverse)
+}
+
+// walkNode visits the node and its children, it returns false if the walk was stopped.
+func walkNode(nr *nodeRef, visitor Visitor, reverse bool) bool {
+	switch visitor.Enter(nr) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		visitor.Leave(nr)
+
+		return true
+	case WalkContinue:
+	}
+
+	if !nr.isLeaf() {
+		zc := nr.zeroChild()
+
+		// the zero byte child goes first in ascending order and last in descending order.
+		if zc != nil && !reverse && !walkNode(zc, visitor, reverse) {
+			return false
+		}
+
+		if !nr.rangeChildren(reverse, func(_ byte, child *nodeRef) bool {
+			return walkNode(child, visitor, reverse)
+		}) {
+			return false
+		}
+
+		if zc != nil && reverse && !walkNode(zc, visitor, reverse) {
+			return false
+		}
+	}
+
+	visitor.Leave(nr)
+
+	return true
+}
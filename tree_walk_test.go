@@ -0,0 +1,124 @@
+package art
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingVisitor records the Enter/Leave events and returns the configured actions.
+type recordingVisitor struct {
+	events  []string
+	actions map[string]WalkAction
+}
+
+func nodeName(node Node) string {
+	if node.Kind() == Leaf {
+		return string(node.Key())
+	}
+
+	return fmt.Sprintf("%s(%s)", node.Kind(), node.(InnerNode).Path())
+}
+
+func (v *recordingVisitor) Enter(node Node) WalkAction {
+	v.events = append(v.events, "+"+nodeName(node))
+
+	return v.actions[nodeName(node)]
+}
+
+func (v *recordingVisitor) Leave(node Node) {
+	v.events = append(v.events, "-"+nodeName(node))
+}
+
+func TestTreeWalk(t *testing.T) {
+	t.Parallel()
+
+	v := &recordingVisitor{}
+	require.NoError(t, Walk(treeWithKeys("a", "ab", "ac", "b"), v))
+	assert.Equal(t, []string{
+		"+Node4()", "+Node4(a)", "+a", "-a", "+ab", "-ab", "+ac", "-ac", "-Node4(a)", "+b", "-b", "-Node4()",
+	}, v.events)
+
+	v = &recordingVisitor{}
+	require.NoError(t, Walk(treeWithKeys("a", "ab", "ac", "b"), v, TraverseReverse))
+	assert.Equal(t, []string{
+		"+Node4()", "+b", "-b", "+Node4(a)", "+ac", "-ac", "+ab", "-ab", "+a", "-a", "-Node4(a)", "-Node4()",
+	}, v.events)
+}
+
+func TestTreeWalkActions(t *testing.T) {
+	t.Parallel()
+
+	v := &recordingVisitor{actions: map[string]WalkAction{"Node4(a)": WalkSkipChildren}}
+	require.NoError(t, Walk(treeWithKeys("a", "ab", "ac", "b"), v))
+	assert.Equal(t, []string{"+Node4()", "+Node4(a)", "-Node4(a)", "+b", "-b", "-Node4()"}, v.events)
+
+	v = &recordingVisitor{actions: map[string]WalkAction{"ab": WalkStop}}
+	require.NoError(t, Walk(treeWithKeys("a", "ab", "ac", "b"), v))
+	assert.Equal(t, []string{"+Node4()", "+Node4(a)", "+a", "-a", "+ab"}, v.events)
+
+	v = &recordingVisitor{}
+	require.NoError(t, Walk(New(), v))
+	assert.Empty(t, v.events)
+}
+
+func TestTreeWalkPostOrderAggregation(t *testing.T) {
+	t.Parallel()
+
+	tree, words := treeWithData("test/assets/words.txt")
+
+	// count leaves of every subtree on the way up.
+	var (
+		stack  []int
+		result int
+		leaves []string
+	)
+
+	err := Walk(tree, WalkFuncs{
+		EnterFunc: func(node Node) WalkAction {
+			stack = append(stack, 0)
+
+			return WalkContinue
+		},
+		LeaveFunc: func(node Node) {
+			count := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if node.Kind() == Leaf {
+				count = 1
+				leaves = append(leaves, string(node.Key()))
+			}
+
+			if len(stack) > 0 {
+				stack[len(stack)-1] += count
+			} else {
+				result = count
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, len(words), result)
+
+	var expected []string
+
+	tree.ForEach(func(node Node) bool {
+		expected = append(expected, string(node.Key()))
+
+		return true
+	})
+	assert.Equal(t, expected, leaves)
+}
+
+func TestTreeWalkUnsupportedTree(t *testing.T) {
+	t.Parallel()
+
+	// a custom implementation of the Tree interface.
+	wrapped := struct{ Tree }{treeWithKeys("a")}
+
+	v := &recordingVisitor{}
+	require.ErrorIs(t, Walk(wrapped, v), ErrUnsupportedTree)
+	assert.Empty(t, v.events)
+}
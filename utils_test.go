@@ -168,3 +168,20 @@ func treeWithData(filePath string) (*tree, [][]byte) {
 
 	return tree, data
 }
+
+// treeWithKeys creates a tree with the given keys, the value of every key is the key string.
+func treeWithKeys(keys ...string) *tree {
+	tree := newTree()
+	for _, key := range keys {
+		tree.Insert(Key(key), key)
+	}
+
+	return tree
+}
+
+// readOnlyTrees returns the tree with the given keys and its frozen copy.
+func readOnlyTrees(keys ...string) map[string]ReadOnlyTree {
+	tree := treeWithKeys(keys...)
+
+	return map[string]ReadOnlyTree{"Tree": tree, "Frozen": Freeze(tree)}
+}